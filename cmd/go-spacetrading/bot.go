@@ -0,0 +1,284 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ianlshaw/go-spacetrading/spacetraders"
+)
+
+// Bot drives the fleet of a single agent.
+type Bot struct {
+	client *spacetraders.Client
+
+	// ships take their turns concurrently, mu guards the maps and slices below once Bootstrap is done
+	mu sync.Mutex
+
+	// ship turns hold books for reading, so reconciling the ledger never catches a transaction half recorded
+	books sync.RWMutex
+
+	// the system the agent's first ship started in, the bot does not leave it
+	system_symbol string
+
+	// each unique market waypoint symbol, and the satellite assigned to watch it
+	markets_to_cover map[string]string
+
+	// there can be multiple SHIPYARDs which sell SHIP_PROBE
+	probe_shipyards []spacetraders.Waypoint
+
+	// association for places to BUY and SELL TradeGoods
+	trade_routes []TradeRoute
+
+	// the trip each trading ship is on, by ship symbol
+	trades map[string]TradeTrip
+
+	// the last GetMarket result for every market, shared with any other agents in the process
+	intel *MarketIntel
+
+	// the asteroid miners work at, and the surveys they have made of it
+	mining_waypoint string
+	surveys         []spacetraders.Survey
+
+	// the jump gate network, charted as ships need to leave the system
+	graph *spacetraders.SystemGraph
+
+	// every waypoint looked up so far, by symbol
+	waypoints map[string]spacetraders.Waypoint
+
+	// every transaction the fleet has made
+	ledger *Ledger
+
+	// where the bot's state is saved after every turn, empty to not save it
+	state_file string
+
+	// how the agent signed up, nil if it cannot sign up again after a server reset
+	account *Account
+
+	// stops the trade routes following the intel, see follow_intel
+	unsubscribe func()
+
+	// the roles ships can play by name, and which ship symbols or registration roles play which
+	roles       map[string]Role
+	assignments map[string]string
+
+	// ships bought during a turn, so Run starts them straight away rather than at the next turn_length
+	purchased chan spacetraders.Ship
+}
+
+func NewBot(client *spacetraders.Client, intel *MarketIntel) *Bot {
+	bot := &Bot{
+		client:           client,
+		intel:            intel,
+		markets_to_cover: make(map[string]string),
+		trades:           make(map[string]TradeTrip),
+		graph:            spacetraders.NewSystemGraph(),
+		waypoints:        make(map[string]spacetraders.Waypoint),
+		ledger:           NewLedger(),
+		roles:            make(map[string]Role),
+		assignments:      make(map[string]string),
+		purchased:        make(chan spacetraders.Ship, 16),
+	}
+	client.OnTransaction = bot.record_transaction
+	bot.register_default_roles()
+	return bot
+}
+
+func (bot *Bot) populate_system_symbol(ctx context.Context) error {
+	ships, err := bot.client.ListShips(ctx)
+	if err != nil {
+		return err
+	}
+	if len(ships) == 0 {
+		return errors.New("populate_system_symbol: agent has no ships")
+	}
+	bot.system_symbol = ships[0].Nav.SystemSymbol
+	return nil
+}
+
+// Bootstrap scans every market and shipyard in the home system to find trade routes
+// and the shipyards which sell satellites.
+func (bot *Bot) Bootstrap(ctx context.Context) error {
+	if err := bot.populate_system_symbol(ctx); err != nil {
+		return err
+	}
+
+	// cache for full response from every get_market call
+	all_market_results := []spacetraders.Market{}
+
+	// populate all_market results with the result of get_market against each waypoint which has a MARKETPLACE
+	marketplaces_in_system, err := bot.client.ListWaypointsInSystemByTrait(ctx, bot.system_symbol, spacetraders.WaypointTraitMarketplace)
+	if err != nil {
+		return err
+	}
+	for _, marketplace := range marketplaces_in_system {
+		bot.waypoints[marketplace.Symbol] = marketplace
+		get_market_result, err := bot.client.GetMarket(ctx, bot.system_symbol, marketplace.Symbol)
+		if err != nil {
+			return err
+		}
+		all_market_results = append(all_market_results, get_market_result)
+		bot.record_market(get_market_result)
+	}
+
+	// association for places to BUY and SELL TradeGoods
+	bot.trade_routes = []TradeRoute{}
+
+	// each unique market waypoint symbol (unordered)
+	bot.markets_to_cover = make(map[string]string)
+
+	// populate both trade_routes and markets_to_cover by iterating through A) every MARKETPLACE B) each of their Imports and C) their Exports
+	// associations of import/export are added to trade_routes, and we keep one copy of each waypoint_symbol in markets_to_cover
+	for _, each_market_result := range all_market_results {
+		if len(each_market_result.Exports) > 0 {
+			for _, each_export := range each_market_result.Exports {
+				for _, each_market_result_inner := range all_market_results {
+					if len(each_market_result_inner.Imports) > 0 {
+						for _, each_market_result_imports := range each_market_result_inner.Imports {
+							if each_export.Symbol == each_market_result_imports.Symbol {
+
+								fmt.Print("[DEBUG] TRADE ROUTE FOUND BUY ")
+								fmt.Print(each_export.Symbol)
+								fmt.Print(" AT ")
+								fmt.Print(each_market_result.Symbol)
+								fmt.Print(" SELL AT ")
+								fmt.Print(each_market_result_inner.Symbol)
+								fmt.Println()
+
+								trade_route := TradeRoute{}
+								trade_route.TradeGoodSymbol = each_export.Symbol
+								trade_route.BuyMarketplaceWaypointSymbol = each_market_result.Symbol
+								trade_route.SellMarketplaceWaypointSymbol = each_market_result_inner.Symbol
+								bot.trade_routes = append(bot.trade_routes, trade_route)
+								bot.markets_to_cover[trade_route.BuyMarketplaceWaypointSymbol] = ""
+								bot.markets_to_cover[trade_route.SellMarketplaceWaypointSymbol] = ""
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	if err := bot.PopulateTradeRoutesWithWaypointData(ctx); err != nil {
+		return err
+	}
+	PopulateTradeRoutesWithDistances(bot.trade_routes)
+	for _, market := range all_market_results {
+		ApplyMarketToTradeRoutes(bot.trade_routes, market)
+	}
+	bot.warm_start_trade_routes()
+
+	// there can be multiple SHIPYARDs which sell SHIP_PROBE
+	bot.probe_shipyards = []spacetraders.Waypoint{}
+
+	// populate probe_shipyards with Waypoints which have SHIPYARDs which sell SHIP_PROBEs
+	shipyards_in_system, err := bot.client.ListWaypointsInSystemByTrait(ctx, bot.system_symbol, spacetraders.WaypointTraitShipyard)
+	if err != nil {
+		return err
+	}
+	for _, shipyard_waypoint := range shipyards_in_system {
+		bot.waypoints[shipyard_waypoint.Symbol] = shipyard_waypoint
+		get_shipyard_result, err := bot.client.GetShipyard(ctx, bot.system_symbol, shipyard_waypoint.Symbol)
+		if err != nil {
+			return err
+		}
+		for _, ship := range get_shipyard_result.ShipTypes {
+			if ship.Type == spacetraders.ShipTypeProbe {
+				fmt.Println("[INFO] shipyard with satellites for sale found: ")
+				fmt.Println("[INFO] " + get_shipyard_result.Symbol)
+				bot.probe_shipyards = append(bot.probe_shipyards, shipyard_waypoint)
+			}
+		}
+	}
+
+	fmt.Println("[DEBUG] markets to cover:")
+
+	for _, market := range sorted_market_symbols(bot.markets_to_cover) {
+		fmt.Println("[DEBUG] " + market)
+	}
+
+	return nil
+}
+
+// RunTurns walks the fleet one ship at a time, turn after turn, until ctx is cancelled. The same game
+// always gets the same calls in the same order, which is what a recorded session needs to be replayed.
+func (bot *Bot) RunTurns(ctx context.Context) {
+	turn_number := 1
+
+	fmt.Print("[INFO] http calls: ")
+	fmt.Print(bot.client.Calls())
+	bot.client.ResetCalls()
+	fmt.Println()
+
+	// this runs until we are told to stop
+	for ctx.Err() == nil {
+
+		turn_start := time.Now()
+
+		fmt.Print("[INFO] START OF TURN ")
+		fmt.Print(turn_number)
+		fmt.Println()
+
+		agent, err := bot.client.GetAgent(ctx)
+		if err != nil {
+			// a failed turn is retried on the next one rather than taking the whole fleet down
+			fmt.Println("[ERROR] " + err.Error())
+			sleep(ctx, time.Duration(turn_length)*time.Second)
+			continue
+		}
+
+		fmt.Println("[INFO] " + agent.Symbol)
+		fmt.Print("[INFO] ShipCount: ")
+		fmt.Print(agent.ShipCount)
+		fmt.Println()
+		fmt.Print("[INFO] Credits: ")
+		fmt.Print(agent.Credits)
+		fmt.Println()
+		bot.ledger.Reconcile(agent.Credits)
+
+		ships_list, err := bot.client.ListShips(ctx)
+		if err != nil || len(ships_list) == 0 {
+			fmt.Println("[ERROR] could not list ships", err)
+			sleep(ctx, time.Duration(turn_length)*time.Second)
+			continue
+		}
+
+		for _, ship := range ships_list {
+			if ctx.Err() != nil {
+				break
+			}
+			if err := bot.PlayShip(ctx, ship); err != nil {
+				fmt.Println("[ERROR] " + ship.Symbol + ": " + err.Error())
+			}
+		}
+
+		// outro
+		bot.ledger.PrintReport()
+		if err := bot.SaveState(); err != nil {
+			fmt.Println("[ERROR] saving state: " + err.Error())
+		}
+
+		// calls are paced by the client's rate limiter, so the turn takes as long as its http calls need
+		fmt.Print("[INFO] http calls: ")
+		fmt.Print(bot.client.Calls())
+		fmt.Print(" in ")
+		fmt.Print(time.Since(turn_start).Round(time.Second))
+		fmt.Println()
+		fmt.Println("[INFO] END OF TURN")
+
+		// reset call counter
+		bot.client.ResetCalls()
+		turn_number++
+	}
+}
+
+// sleep waits for d, returning early if ctx is cancelled.
+func sleep(ctx context.Context, d time.Duration) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(d):
+	}
+}
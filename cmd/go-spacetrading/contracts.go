@@ -0,0 +1,245 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ianlshaw/go-spacetrading/spacetraders"
+)
+
+// ActiveContract returns the accepted contract which is still to be fulfilled, or failing that the
+// first offer which can still be accepted. Contracts past their deadline are given up on. found is
+// false if there is neither.
+func ActiveContract(contracts []spacetraders.Contract, now time.Time) (contract spacetraders.Contract, found bool) {
+	for _, contract := range contracts {
+		if contract.Accepted && !contract.Fulfilled && !past_deadline(contract, now) {
+			return contract, true
+		}
+	}
+	for _, contract := range contracts {
+		if contract.Accepted || contract.Fulfilled || past_deadline(contract, now) {
+			continue
+		}
+		if !contract.DeadlineToAccept.IsZero() && now.After(contract.DeadlineToAccept.Time) {
+			continue
+		}
+		return contract, true
+	}
+	return spacetraders.Contract{}, false
+}
+
+func past_deadline(contract spacetraders.Contract, now time.Time) bool {
+	return !contract.Terms.Deadline.IsZero() && now.After(contract.Terms.Deadline.Time)
+}
+
+// ContractDeliveryTime estimates how long the ship takes to carry units from the source market to
+// destination at CRUISE: getting to the source, then a hold full at a time, coming back for the next.
+func ContractDeliveryTime(ship spacetraders.Ship, source TradeRoute, destination spacetraders.Waypoint, units int64) time.Duration {
+	here := ship.Nav.Route.Destination
+	to_source := spacetraders.DistanceBetweenTwoCoordinates(here.X, here.Y, source.BuyWaypoint.X, source.BuyWaypoint.Y)
+	loaded := spacetraders.DistanceBetweenTwoWaypoints(source.BuyWaypoint, destination)
+
+	capacity := ship.Cargo.Capacity
+	if capacity < 1 {
+		capacity = 1
+	}
+	trips := (units + capacity - 1) / capacity
+	leg := spacetraders.TravelTime(loaded, ship.Engine.Speed, spacetraders.FlightModeCruise)
+	return spacetraders.TravelTime(to_source, ship.Engine.Speed, spacetraders.FlightModeCruise) + leg*time.Duration(2*trips-1)
+}
+
+// deliverable_in_time reports whether the ship could deliver everything the contract asks for before
+// its deadline, buying from the cheapest markets the satellites know of. Until they know where to buy
+// every good, it cannot tell and says no.
+func (bot *Bot) deliverable_in_time(ctx context.Context, ship spacetraders.Ship, contract spacetraders.Contract) (bool, error) {
+	var needed time.Duration
+	for _, deliver := range contract.Terms.Deliver {
+		units := deliver.UnitsRequired - deliver.UnitsFulfilled
+		if units <= 0 {
+			continue
+		}
+		source, known := CheapestKnownMarket(bot.trade_routes_snapshot(), deliver.TradeSymbol)
+		if !known {
+			fmt.Println("[INFO] No known market sells " + deliver.TradeSymbol + " yet, waiting for satellites")
+			return false, nil
+		}
+		destination, err := bot.waypoint(ctx, deliver.DestinationSymbol)
+		if err != nil {
+			return false, err
+		}
+		needed += ContractDeliveryTime(ship, source, destination, units)
+	}
+	if !contract.Terms.Deadline.IsZero() && bot.client.Now().Add(needed).After(contract.Terms.Deadline.Time) {
+		fmt.Println("[INFO] Contract " + contract.ID + " could not be delivered before its deadline")
+		return false, nil
+	}
+	return true, nil
+}
+
+// OutstandingDelivery returns the first delivery of the contract with units still to be handed over.
+func OutstandingDelivery(contract spacetraders.Contract) (spacetraders.Deliver, bool) {
+	for _, deliver := range contract.Terms.Deliver {
+		if deliver.UnitsFulfilled < deliver.UnitsRequired {
+			return deliver, true
+		}
+	}
+	return spacetraders.Deliver{}, false
+}
+
+// ApplyRoleContract works through the agent's contracts: accepting offers, buying the goods required
+// from the cheapest market the satellites know of, delivering them and collecting payment.
+// When there is no contract to work on it negotiates a new one at headquarters.
+func (bot *Bot) ApplyRoleContract(ctx context.Context, ship spacetraders.Ship) error {
+	fmt.Println("[INFO] " + ship.Symbol)
+
+	if ship.Nav.Status == spacetraders.NavStatusInTransit {
+		bot.print_in_transit(ship)
+		return nil
+	}
+
+	contracts, err := bot.client.ListContracts(ctx)
+	if err != nil {
+		return err
+	}
+
+	contract, found := ActiveContract(contracts, bot.client.Now())
+	if !found {
+		return bot.negotiate_contract(ctx, ship)
+	}
+	ctx = with_ledger_route(ctx, "CONTRACT "+contract.ID)
+
+	if !contract.Accepted {
+		in_time, err := bot.deliverable_in_time(ctx, ship, contract)
+		if err != nil {
+			return err
+		}
+		if !in_time {
+			return nil
+		}
+		fmt.Println("[INFO] Accepting contract " + contract.ID)
+		accepted, err := bot.client.AcceptContract(ctx, contract.ID)
+		if err != nil {
+			return err
+		}
+		contract = accepted.Contract
+		bot.record_contract_payment(ctx, ship, contract, transaction_type_contract_accepted, contract.Terms.Payment.OnAccepted, accepted.Agent)
+	}
+
+	deliver, outstanding := OutstandingDelivery(contract)
+	if !outstanding {
+		fmt.Println("[INFO] Fulfilling contract " + contract.ID)
+		if !IsShipDocked(ship) {
+			docked, err := bot.client.DockShip(ctx, ship.Symbol)
+			if err != nil {
+				return ignore_in_transit(ship.Symbol, err)
+			}
+			ship.Nav = docked.Nav
+		}
+		fulfilled, err := bot.client.FulfillContract(ctx, contract.ID)
+		if err != nil {
+			return err
+		}
+		bot.record_contract_payment(ctx, ship, contract, transaction_type_contract_fulfilled, contract.Terms.Payment.OnFulfilled, fulfilled.Agent)
+		fmt.Print("[INFO] Contract paid, credits: ")
+		fmt.Println(fulfilled.Agent.Credits)
+		return nil
+	}
+
+	units_still_needed := deliver.UnitsRequired - deliver.UnitsFulfilled
+	units_in_cargo_hold := CountTradeGoodCargo(ship, deliver.TradeSymbol)
+	space_in_cargo_hold := ship.Cargo.Capacity - ship.Cargo.Units
+
+	// deliver once the hold is full or holds everything the contract still needs, and whatever is in the
+	// hold when the ship is at the destination anyway, such as a load cut short by running out of credits
+	at_destination := IsShipAlreadyAtWaypoint(ship, deliver.DestinationSymbol)
+	if units_in_cargo_hold > 0 && (at_destination || units_in_cargo_hold >= units_still_needed || space_in_cargo_hold == 0) {
+		if !at_destination {
+			fmt.Println("[INFO] Taking " + string(deliver.TradeSymbol) + " to " + deliver.DestinationSymbol)
+			return bot.depart(ctx, ship, deliver.DestinationSymbol)
+		}
+		if !IsShipDocked(ship) {
+			docked, err := bot.client.DockShip(ctx, ship.Symbol)
+			if err != nil {
+				return ignore_in_transit(ship.Symbol, err)
+			}
+			ship.Nav = docked.Nav
+		}
+		units_to_deliver := units_in_cargo_hold
+		if units_to_deliver > units_still_needed {
+			units_to_deliver = units_still_needed
+		}
+		fmt.Print("[INFO] Delivering " + deliver.TradeSymbol + " ")
+		fmt.Println(units_to_deliver)
+		_, err := bot.client.DeliverContract(ctx, contract.ID, ship.Symbol, deliver.TradeSymbol, units_to_deliver)
+		return err
+	}
+
+	source, known := CheapestKnownMarket(bot.trade_routes_snapshot(), deliver.TradeSymbol)
+	if !known {
+		fmt.Println("[INFO] No known market sells " + deliver.TradeSymbol + " yet, waiting for satellites")
+		return nil
+	}
+
+	if !IsShipAlreadyAtWaypoint(ship, source.BuyMarketplaceWaypointSymbol) {
+		fmt.Println("[INFO] Heading to " + source.BuyMarketplaceWaypointSymbol + " to buy " + string(deliver.TradeSymbol))
+		return bot.depart(ctx, ship, source.BuyMarketplaceWaypointSymbol)
+	}
+
+	if !IsShipDocked(ship) {
+		docked, err := bot.client.DockShip(ctx, ship.Symbol)
+		if err != nil {
+			return ignore_in_transit(ship.Symbol, err)
+		}
+		ship.Nav = docked.Nav
+	}
+
+	units_to_purchase := units_still_needed - units_in_cargo_hold
+	if units_to_purchase > space_in_cargo_hold {
+		units_to_purchase = space_in_cargo_hold
+	}
+	bought, err := bot.buy_units(ctx, ship.Symbol, deliver.TradeSymbol, units_to_purchase, source.BuyMarketTradeGood.TradeVolume)
+	if err != nil {
+		return err
+	}
+	// out of credits or out of room, flying off empty would only bring the ship straight back
+	if bought == 0 && units_in_cargo_hold == 0 {
+		fmt.Println("[INFO] Could not buy any " + string(deliver.TradeSymbol) + ", staying at " + ship.Nav.WaypointSymbol)
+		return nil
+	}
+
+	fmt.Println("[INFO] Taking " + string(deliver.TradeSymbol) + " to " + deliver.DestinationSymbol)
+	if ship.Fuel.Capacity > 0 && bot.sells_fuel(ship.Nav.WaypointSymbol) {
+		refueled, err := bot.client.RefuelShip(ctx, ship.Symbol)
+		if err != nil {
+			return err
+		}
+		ship.Fuel = refueled.Fuel
+	}
+	return bot.depart(ctx, ship, deliver.DestinationSymbol)
+}
+
+// negotiate_contract heads to headquarters, where the agent's faction hands out new contracts.
+func (bot *Bot) negotiate_contract(ctx context.Context, ship spacetraders.Ship) error {
+	agent, err := bot.client.GetAgent(ctx)
+	if err != nil {
+		return err
+	}
+	if !IsShipAlreadyAtWaypoint(ship, agent.Headquarters) {
+		fmt.Println("[INFO] No contract, heading to headquarters to negotiate one")
+		return bot.depart(ctx, ship, agent.Headquarters)
+	}
+	if !IsShipDocked(ship) {
+		docked, err := bot.client.DockShip(ctx, ship.Symbol)
+		if err != nil {
+			return ignore_in_transit(ship.Symbol, err)
+		}
+		ship.Nav = docked.Nav
+	}
+	contract, err := bot.client.NegotiateContract(ctx, ship.Symbol)
+	if err != nil {
+		return err
+	}
+	fmt.Println("[INFO] Negotiated contract " + contract.ID)
+	return nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/ianlshaw/go-spacetrading/spacetraders"
+)
+
+func HasTrait(waypoint spacetraders.Waypoint, trait spacetraders.WaypointTrait) bool {
+	for _, each_trait := range waypoint.Traits {
+		if each_trait.Symbol == trait {
+			return true
+		}
+	}
+	return false
+}
+
+// unpriced_marketplaces lists the marketplaces in system_symbol nobody has been to yet, so whose prices are unknown.
+func (bot *Bot) unpriced_marketplaces(system_symbol string) []spacetraders.Waypoint {
+	markets := bot.intel.MarketsIn(system_symbol)
+	bot.mu.Lock()
+	defer bot.mu.Unlock()
+	unpriced := []spacetraders.Waypoint{}
+	for _, symbol := range sorted_waypoint_symbols(bot.waypoints) {
+		waypoint := bot.waypoints[symbol]
+		if waypoint.SystemSymbol != system_symbol || !HasTrait(waypoint, spacetraders.WaypointTraitMarketplace) {
+			continue
+		}
+		if len(markets[symbol].TradeGoods) == 0 {
+			unpriced = append(unpriced, waypoint)
+		}
+	}
+	return unpriced
+}
+
+func sorted_waypoint_symbols(waypoints map[string]spacetraders.Waypoint) []string {
+	symbols := make([]string, 0, len(waypoints))
+	for symbol := range waypoints {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// ApplyRoleExplorer charts the jump gate network around the ship and visits every marketplace in its
+// system that nobody has priced yet, nearest first.
+func (bot *Bot) ApplyRoleExplorer(ctx context.Context, ship spacetraders.Ship) error {
+	fmt.Println("[INFO] " + ship.Symbol)
+
+	if ship.Nav.Status == spacetraders.NavStatusInTransit {
+		bot.print_in_transit(ship)
+		return nil
+	}
+
+	if err := bot.client.ExploreJumpGates(ctx, bot.graph, ship.Nav.SystemSymbol, jump_search_depth); err != nil {
+		return err
+	}
+
+	unpriced := bot.unpriced_marketplaces(ship.Nav.SystemSymbol)
+	if len(unpriced) == 0 {
+		fmt.Println("[INFO] Every marketplace in " + ship.Nav.SystemSymbol + " has been priced")
+		return nil
+	}
+
+	here := ship.Nav.Route.Destination
+	nearest := unpriced[0]
+	for _, waypoint := range unpriced {
+		if waypoint.Symbol == ship.Nav.WaypointSymbol {
+			nearest = waypoint
+			break
+		}
+		if spacetraders.DistanceBetweenTwoCoordinates(here.X, here.Y, waypoint.X, waypoint.Y) < spacetraders.DistanceBetweenTwoCoordinates(here.X, here.Y, nearest.X, nearest.Y) {
+			nearest = waypoint
+		}
+	}
+
+	if !IsShipAlreadyAtWaypoint(ship, nearest.Symbol) {
+		fmt.Println("[INFO] Exploring " + nearest.Symbol)
+		return bot.depart(ctx, ship, nearest.Symbol)
+	}
+	if !IsShipDocked(ship) {
+		if _, err := bot.client.DockShip(ctx, ship.Symbol); err != nil {
+			return ignore_in_transit(ship.Symbol, err)
+		}
+	}
+	fmt.Println("[INFO] Pricing " + nearest.Symbol)
+	return bot.UpdateTradeRoutesIncludingThisWaypoint(ctx, nearest.Symbol)
+}
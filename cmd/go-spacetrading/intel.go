@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ianlshaw/go-spacetrading/spacetraders"
+)
+
+// MarketIntel is what every agent in the process knows about markets. Agents share one, so a satellite
+// belonging to one agent prices markets for the traders of all of them.
+type MarketIntel struct {
+	mu sync.Mutex
+
+	// the last look anyone had at each market, by waypoint symbol
+	markets map[string]spacetraders.Market
+
+	// called with every market recorded by subscription number, see Subscribe
+	subscribers   map[int]func(spacetraders.Market)
+	subscriptions int
+
+	// the server reset the markets were seen after, see NewUniverse
+	reset_date string
+
+	// where market snapshots are kept between runs, nil to not keep them
+	history *spacetraders.MarketHistory
+}
+
+func NewMarketIntel() *MarketIntel {
+	return &MarketIntel{
+		markets:     make(map[string]spacetraders.Market),
+		subscribers: make(map[int]func(spacetraders.Market)),
+	}
+}
+
+// Record remembers the latest look at a market, keeps it in the price history if there is one, and
+// passes it on to every subscriber.
+func (intel *MarketIntel) Record(market spacetraders.Market) {
+	intel.mu.Lock()
+	intel.markets[market.Symbol] = market
+	subscribers := make([]func(spacetraders.Market), 0, len(intel.subscribers))
+	for _, subscriber := range intel.subscribers {
+		subscribers = append(subscribers, subscriber)
+	}
+	intel.mu.Unlock()
+
+	if intel.history != nil {
+		if err := intel.history.Record(market, time.Now()); err != nil {
+			fmt.Println("[ERROR] " + err.Error())
+		}
+	}
+	for _, subscriber := range subscribers {
+		subscriber(market)
+	}
+}
+
+// Restore puts back a market seen in an earlier run, unless someone has had a look at it since.
+func (intel *MarketIntel) Restore(market spacetraders.Market) {
+	intel.mu.Lock()
+	defer intel.mu.Unlock()
+	if len(intel.markets[market.Symbol].TradeGoods) == 0 {
+		intel.markets[market.Symbol] = market
+	}
+}
+
+// Subscribe has every market recorded from now on passed to subscriber, starting with the ones already known,
+// until the returned func is called. subscriber must not call back into the intel.
+func (intel *MarketIntel) Subscribe(subscriber func(spacetraders.Market)) (unsubscribe func()) {
+	intel.mu.Lock()
+	defer intel.mu.Unlock()
+	for _, symbol := range sorted_market_symbols_of(intel.markets) {
+		subscriber(intel.markets[symbol])
+	}
+	intel.subscriptions++
+	subscription := intel.subscriptions
+	intel.subscribers[subscription] = subscriber
+	return func() {
+		intel.mu.Lock()
+		defer intel.mu.Unlock()
+		delete(intel.subscribers, subscription)
+	}
+}
+
+// NewUniverse forgets every market seen before the server reset on reset_date, the waypoints they were
+// at are gone. Every agent starting over calls it, only the first one to do so for a reset clears anything.
+func (intel *MarketIntel) NewUniverse(reset_date string) {
+	intel.mu.Lock()
+	defer intel.mu.Unlock()
+	if intel.reset_date == reset_date {
+		return
+	}
+	intel.reset_date = reset_date
+	intel.markets = make(map[string]spacetraders.Market)
+}
+
+// Market returns the last look anyone had at the market at waypoint_symbol.
+func (intel *MarketIntel) Market(waypoint_symbol string) (spacetraders.Market, bool) {
+	intel.mu.Lock()
+	defer intel.mu.Unlock()
+	market, ok := intel.markets[waypoint_symbol]
+	return market, ok
+}
+
+// MarketsIn copies the markets known in system_symbol.
+func (intel *MarketIntel) MarketsIn(system_symbol string) map[string]spacetraders.Market {
+	intel.mu.Lock()
+	defer intel.mu.Unlock()
+	markets := make(map[string]spacetraders.Market)
+	for symbol, market := range intel.markets {
+		if spacetraders.SystemSymbolOf(symbol) == system_symbol {
+			markets[symbol] = market
+		}
+	}
+	return markets
+}
+
+func sorted_market_symbols_of(markets map[string]spacetraders.Market) []string {
+	symbols := make([]string, 0, len(markets))
+	for symbol := range markets {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ianlshaw/go-spacetrading/spacetraders"
+)
+
+// LedgerEntry is one change to the agent's credits, with what the bot was doing when it happened.
+type LedgerEntry struct {
+	Timestamp  time.Time `json:"timestamp"`
+	ShipSymbol string    `json:"shipSymbol"`
+	Role       string    `json:"role"`
+	Route      string    `json:"route,omitempty"`
+	// trips are numbered per ship, a new one starts with whatever the ship does after selling
+	Trip int `json:"trip"`
+	// what the transaction did to the agent's credits, negative for spending
+	Credits      int64                    `json:"credits"`
+	AgentCredits int64                    `json:"agentCredits"`
+	Transaction  spacetraders.Transaction `json:"transaction"`
+}
+
+// ledger_tag is what the bot is doing with a ship, carried on the context so transactions can be labelled.
+type ledger_tag struct {
+	ship_symbol string
+	role        string
+	route       string
+}
+
+type ledger_tag_key struct{}
+
+func with_ledger_ship(ctx context.Context, ship_symbol string, role string) context.Context {
+	return context.WithValue(ctx, ledger_tag_key{}, ledger_tag{ship_symbol: ship_symbol, role: role})
+}
+
+func with_ledger_route(ctx context.Context, route string) context.Context {
+	tag, _ := ctx.Value(ledger_tag_key{}).(ledger_tag)
+	tag.route = route
+	return context.WithValue(ctx, ledger_tag_key{}, tag)
+}
+
+// TradeRouteLabel names a trade route in the ledger.
+func TradeRouteLabel(trade_route TradeRoute) string {
+	return string(trade_route.TradeGoodSymbol) + " " + trade_route.BuyMarketplaceWaypointSymbol + ">" + trade_route.SellMarketplaceWaypointSymbol
+}
+
+// Ledger is an append-only record of every transaction, kept in memory and optionally in a file
+// one JSON object per line. It is safe for concurrent use.
+type Ledger struct {
+	mu       sync.Mutex
+	file     *os.File
+	filename string
+	entries  []LedgerEntry
+
+	trips     map[string]int                          // current trip by ship symbol
+	last_type map[string]spacetraders.TransactionType // type of the last transaction by ship symbol
+
+	// the credits the agent had when last reconciled, and what has been recorded since
+	reconciled     bool
+	balance        int64
+	since_balanced int64
+}
+
+func NewLedger() *Ledger {
+	return &Ledger{
+		trips:     make(map[string]int),
+		last_type: make(map[string]spacetraders.TransactionType),
+	}
+}
+
+// OpenLedger loads the ledger at filename, creating it if needed, and appends to it from then on.
+// Trips carry on being numbered from where the file left off.
+func OpenLedger(filename string) (*Ledger, error) {
+	ledger := NewLedger()
+
+	existing, err := os.Open(filename)
+	if err == nil {
+		scanner := bufio.NewScanner(existing)
+		line_number := 0
+		for scanner.Scan() {
+			line_number++
+			if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+				continue
+			}
+			entry := LedgerEntry{}
+			if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+				fmt.Printf("[ERROR] ledger %s line %d: %s\n", filename, line_number, err)
+				continue
+			}
+			ledger.entries = append(ledger.entries, entry)
+			ledger.trips[entry.ShipSymbol] = entry.Trip
+			ledger.last_type[entry.ShipSymbol] = entry.Transaction.Type
+		}
+		existing.Close()
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	ledger.file, err = os.OpenFile(filename, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	ledger.filename = filename
+	return ledger, nil
+}
+
+// Record adds a transaction to the ledger, labelled with the ship, role and route on ctx. Transactions
+// made outside a ship's turn are put down to the ship named in the transaction.
+func (ledger *Ledger) Record(ctx context.Context, transaction spacetraders.Transaction, credits int64, agent spacetraders.Agent) error {
+	tag, _ := ctx.Value(ledger_tag_key{}).(ledger_tag)
+	if tag.ship_symbol == "" {
+		tag.ship_symbol = transaction.ShipSymbol
+	}
+	timestamp := transaction.Timestamp.UTC()
+	if transaction.Timestamp.IsZero() {
+		timestamp = time.Now().UTC()
+	}
+
+	ledger.mu.Lock()
+	defer ledger.mu.Unlock()
+
+	ship_symbol := tag.ship_symbol
+	if ledger.last_type[ship_symbol] == spacetraders.TransactionTypeSell && transaction.Type != spacetraders.TransactionTypeSell {
+		ledger.print_trip(ship_symbol, ledger.trips[ship_symbol])
+		ledger.trips[ship_symbol]++
+	}
+	ledger.last_type[ship_symbol] = transaction.Type
+
+	entry := LedgerEntry{
+		Timestamp:    timestamp,
+		ShipSymbol:   ship_symbol,
+		Role:         tag.role,
+		Route:        tag.route,
+		Trip:         ledger.trips[ship_symbol],
+		Credits:      credits,
+		AgentCredits: agent.Credits,
+		Transaction:  transaction,
+	}
+	ledger.entries = append(ledger.entries, entry)
+	ledger.since_balanced += credits
+
+	if ledger.file == nil {
+		return nil
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if _, err := ledger.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("ledger: %w", err)
+	}
+	return nil
+}
+
+func (ledger *Ledger) print_trip(ship_symbol string, trip int) {
+	var profit int64
+	for _, entry := range ledger.entries {
+		if entry.ShipSymbol == ship_symbol && entry.Trip == trip {
+			profit += entry.Credits
+		}
+	}
+	fmt.Printf("[INFO] %s trip %d P&L: %d\n", ship_symbol, trip, profit)
+}
+
+// Reconcile checks the agent's credits against what the ledger says they should be since the last
+// call, and reports any difference, which means credits changed without the ledger hearing about it.
+// It returns the difference.
+func (ledger *Ledger) Reconcile(agent_credits int64) int64 {
+	ledger.mu.Lock()
+	defer ledger.mu.Unlock()
+
+	var difference int64
+	if ledger.reconciled {
+		difference = agent_credits - (ledger.balance + ledger.since_balanced)
+		if difference == 0 {
+			fmt.Println("[INFO] Ledger reconciles with credits")
+		} else {
+			fmt.Printf("[ERROR] Ledger is out by %d credits, expected %d have %d\n", difference, ledger.balance+ledger.since_balanced, agent_credits)
+		}
+	}
+	ledger.reconciled = true
+	ledger.balance = agent_credits
+	ledger.since_balanced = 0
+	return difference
+}
+
+// Recorded is how many transactions the ledger holds.
+func (ledger *Ledger) Recorded() int {
+	ledger.mu.Lock()
+	defer ledger.mu.Unlock()
+	return len(ledger.entries)
+}
+
+// profit_by adds up the credits of every entry under the key it is given.
+func (ledger *Ledger) profit_by(key func(LedgerEntry) string) map[string]int64 {
+	ledger.mu.Lock()
+	defer ledger.mu.Unlock()
+	profits := make(map[string]int64)
+	for _, entry := range ledger.entries {
+		profits[key(entry)] += entry.Credits
+	}
+	return profits
+}
+
+// ProfitByShip is the realised P&L of every ship.
+func (ledger *Ledger) ProfitByShip() map[string]int64 {
+	return ledger.profit_by(func(entry LedgerEntry) string { return entry.ShipSymbol })
+}
+
+// ProfitByRoute is the realised P&L of every route. Transactions made off any route count towards the role
+// of the ship which made them, and those made outside any ship's turn towards OTHER.
+func (ledger *Ledger) ProfitByRoute() map[string]int64 {
+	return ledger.profit_by(func(entry LedgerEntry) string {
+		if entry.Route != "" {
+			return entry.Route
+		}
+		if entry.Role != "" {
+			return entry.Role
+		}
+		return "OTHER"
+	})
+}
+
+// ProfitByTrip is the realised P&L of every trip, keyed SHIP#TRIP.
+func (ledger *Ledger) ProfitByTrip() map[string]int64 {
+	return ledger.profit_by(func(entry LedgerEntry) string { return fmt.Sprintf("%s#%d", entry.ShipSymbol, entry.Trip) })
+}
+
+func print_profits(heading string, profits map[string]int64) {
+	keys := make([]string, 0, len(profits))
+	for k := range profits {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Printf("[INFO] %s %-40s %d\n", heading, k, profits[k])
+	}
+}
+
+// PrintReport prints the P&L of every ship and route.
+func (ledger *Ledger) PrintReport() {
+	print_profits("P&L SHIP", ledger.ProfitByShip())
+	print_profits("P&L ROUTE", ledger.ProfitByRoute())
+}
+
+func (ledger *Ledger) Close() error {
+	if ledger.file == nil {
+		return nil
+	}
+	return ledger.file.Close()
+}
+
+// record_transaction is hooked up to the client so every transaction the game reports ends up in the ledger.
+func (bot *Bot) record_transaction(ctx context.Context, transaction spacetraders.Transaction, credits int64, agent spacetraders.Agent) {
+	if err := bot.ledger.Record(ctx, transaction, credits, agent); err != nil {
+		fmt.Println("[ERROR] " + err.Error())
+	}
+}
+
+// the ledger's own transaction types for contract payments
+const (
+	transaction_type_contract_accepted  spacetraders.TransactionType = "CONTRACT_ACCEPTED"
+	transaction_type_contract_fulfilled spacetraders.TransactionType = "CONTRACT_FULFILLED"
+)
+
+// record_contract_payment puts a contract payment in the ledger, the game does not report those as transactions.
+// The contract is named by the route ctx is tagged with.
+func (bot *Bot) record_contract_payment(ctx context.Context, ship spacetraders.Ship, contract spacetraders.Contract, payment_type spacetraders.TransactionType, credits int64, agent spacetraders.Agent) {
+	transaction := spacetraders.Transaction{
+		ShipSymbol: ship.Symbol,
+		Type:       payment_type,
+		Units:      1,
+		TotalPrice: credits,
+		Timestamp:  spacetraders.Time{Time: bot.client.Now().UTC()},
+	}
+	bot.record_transaction(ctx, transaction, credits, agent)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ianlshaw/go-spacetrading/spacetraders"
+)
+
+func TestLedgerReconcile(t *testing.T) {
+	tests := []struct {
+		name     string
+		starting int64
+		recorded []int64
+		credits  int64
+		want     int64
+	}{
+		{"nothing happened", 1000, nil, 1000, 0},
+		{"every trade recorded", 1000, []int64{-400, 650}, 1250, 0},
+		{"a purchase went unrecorded", 1000, []int64{650}, 1250, -400},
+		{"credits from nowhere", 1000, nil, 1100, 100},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ledger := NewLedger()
+			// the first call has nothing to check against
+			if difference := ledger.Reconcile(test.starting); difference != 0 {
+				t.Fatalf("first Reconcile = %d, want 0", difference)
+			}
+			for _, credits := range test.recorded {
+				transaction := spacetraders.Transaction{ShipSymbol: "TESTER-1", Type: spacetraders.TransactionTypePurchase}
+				if credits > 0 {
+					transaction.Type = spacetraders.TransactionTypeSell
+				}
+				if err := ledger.Record(context.Background(), transaction, credits, spacetraders.Agent{}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if difference := ledger.Reconcile(test.credits); difference != test.want {
+				t.Errorf("Reconcile(%d) = %d, want %d", test.credits, difference, test.want)
+			}
+			// and the next call starts from the credits the agent really has
+			if difference := ledger.Reconcile(test.credits); difference != 0 {
+				t.Errorf("Reconcile again = %d, want 0", difference)
+			}
+		})
+	}
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/ianlshaw/go-spacetrading/spacetraders"
+	"github.com/ianlshaw/go-spacetrading/spacetraders/fake"
+)
+
+var turn_length = 120
+
+func check(e error) {
+	if e != nil {
+		panic(e)
+	}
+}
+
+func DoesAuthFileExist(callsign string) (result bool) {
+	var filename = callsign + ".token"
+	if _, err := os.Stat(filename); errors.Is(err, os.ErrNotExist) {
+		// path/to/whatever does not exist
+		fmt.Println("[ERROR] Token file does not exist")
+		return false
+	}
+	fmt.Println("[INFO] Token file exists")
+	return true
+}
+
+func WriteAuthTokenToFile(auth_token string, filename string) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	write_string_result, err := f.WriteString(auth_token)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("wrote %d bytes\n", write_string_result)
+	return nil
+}
+
+func read_auth_token_from_file(callsign string) (string, error) {
+	f, err := os.ReadFile(callsign + ".token") // just pass the file name
+	if err != nil {
+		return "", err
+	}
+	return string(f), nil
+}
+
+// where the account token is kept when it is not given with -account-token
+const account_token_file = "account.token"
+
+// read_account_token returns the account token from the -account-token flag or, failing that, from
+// account_token_file. It is empty if neither has one.
+func read_account_token(flag_value string) (string, error) {
+	if flag_value != "" {
+		return flag_value, nil
+	}
+	contents, err := os.ReadFile(account_token_file)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(contents)), nil
+}
+
+// sign_up registers account's agent, stopping the bot with the game's reason if it turns the agent down.
+func sign_up(ctx context.Context, client *spacetraders.Client, account *Account) {
+	if err := account.sign_up(ctx, client); err != nil {
+		fmt.Println("[ERROR] " + err.Error())
+		os.Exit(1)
+	}
+}
+
+// assignments collects every -assign flag, each KEY=ROLE.
+type assignments []string
+
+func (a *assignments) String() string {
+	return strings.Join(*a, ",")
+}
+
+func (a *assignments) Set(value string) error {
+	if !strings.Contains(value, "=") {
+		return errors.New("want SHIP=ROLE or REGISTRATION_ROLE=ROLE")
+	}
+	*a = append(*a, value)
+	return nil
+}
+
+func main() {
+
+	base_url := flag.String("base-url", spacetraders.DefaultBaseURL, "SpaceTraders API to play against")
+	offline := flag.Bool("offline", false, "play against an in-process fake server instead of the real game")
+	record := flag.String("record", "", "append every request and response to this cassette file")
+	replay := flag.String("replay", "", "answer requests from this cassette file instead of the network")
+	history := flag.String("history", "", "keep market prices in this file, shared by every agent (default CALLSIGN.markets.jsonl of the first CALLSIGN when playing the real game without -record)")
+	ledger := flag.String("ledger", "", "keep every transaction in this file (default CALLSIGN.ledger.jsonl when playing the real game)")
+	state := flag.String("state", "", "save the bot's state to this file after every turn and start from it (default CALLSIGN.state.json when playing the real game without -record)")
+	faction := flag.String("faction", spacetraders.DefaultFaction, "faction new agents join")
+	email := flag.String("email", "", "email address to register new agents with, optional")
+	account_token_flag := flag.String("account-token", "", "account token to register new agents with (default the contents of "+account_token_file+")")
+	var role_assignments assignments
+	flag.Var(&role_assignments, "assign", "have a ship, or every ship the game registered with a role, play a role: SHIP=ROLE or HAULER=ROLE, can be repeated")
+	flag.Parse()
+
+	// Ensure at least one CALLSIGN is provided as a command line argument
+	if flag.NArg() < 1 {
+		fmt.Println("go-spacetrade [-offline] [-base-url URL] [-record FILE | -replay FILE] [-history FILE] [-ledger FILE] [-state FILE] [-assign KEY=ROLE ...] [-faction FACTION] [-email EMAIL] [-account-token TOKEN] CALLSIGN [CALLSIGN ...]")
+		os.Exit(1)
+	}
+
+	callsigns := flag.Args()
+	if len(callsigns) > 1 && (*record != "" || *replay != "") {
+		fmt.Println("[ERROR] -record and -replay take a single CALLSIGN, the calls of several agents come in no fixed order")
+		os.Exit(1)
+	}
+	if len(callsigns) > 1 && (*ledger != "" || *state != "") {
+		fmt.Println("[ERROR] -ledger and -state name a single agent's file, leave them out to give each agent its own")
+		os.Exit(1)
+	}
+
+	account_token, err := read_account_token(*account_token_flag)
+	check(err)
+
+	// Ctrl-C cancels outstanding calls and ends the turn loop
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// the fake universe only lives as long as the process, every agent plays in the same one
+	var server *fake.Server
+	if *offline && *replay == "" {
+		server = fake.NewServer()
+		defer server.Close()
+	}
+
+	// every agent trades on what any of them has seen
+	intel := NewMarketIntel()
+	// prices from a fake universe or a replayed session would only muddy the real history, and a
+	// recorded session warm started from it would choose differently to its replay
+	if *history == "" && !*offline && *replay == "" && *record == "" {
+		*history = callsigns[0] + ".markets.jsonl"
+	}
+	if *history != "" {
+		market_history, err := spacetraders.OpenMarketHistory(*history, spacetraders.DefaultHistoryRetention)
+		check(err)
+		defer market_history.Close()
+		intel.history = market_history
+	}
+
+	// every agent's calls come from the same address, so they all wait on the one limit
+	limiter := spacetraders.NewServerRateLimiter()
+
+	bots := []*Bot{}
+	for _, CALLSIGN := range callsigns {
+		client := spacetraders.NewClient("")
+		client.Limiter = limiter
+		client.BaseURL = *base_url
+		client.AccountToken = account_token
+		client.Debug = true
+		registration_payload := spacetraders.RegisterAgentPayload{Symbol: CALLSIGN, Faction: *faction, Email: *email}
+		var account *Account
+
+		ledger_file := *ledger
+		if ledger_file == "" && !*offline && *replay == "" {
+			ledger_file = CALLSIGN + ".ledger.jsonl"
+		}
+		// a recorded session starts from scratch, as its replay will
+		state_file := *state
+		if state_file == "" && !*offline && *replay == "" && *record == "" {
+			state_file = CALLSIGN + ".state.json"
+		}
+
+		if *record != "" {
+			recorder, err := spacetraders.NewRecorder(*record, client.BaseURL, nil)
+			check(err)
+			defer recorder.Close()
+			client.HTTPClient.Transport = recorder
+		}
+
+		if *replay != "" {
+			// the recorded session already waited on the rate limit, and the agent's token was never written to the cassette
+			replayer, err := spacetraders.NewReplayer(*replay, client.BaseURL)
+			check(err)
+			client.HTTPClient.Transport = replayer
+			client.Limiter = nil
+			client.Token = spacetraders.ReplayToken
+			// once the tape runs out the session is over
+			go func() {
+				<-replayer.Done()
+				stop()
+			}()
+			// sessions recorded from a first run start by registering
+			if next, ok := replayer.Peek(); ok && next.Method == "POST" && strings.HasSuffix(next.Path, "/register") {
+				// the cassette hands back its placeholder token, and there is nothing to keep on disk
+				sign_up(ctx, client, &Account{registration: registration_payload})
+			}
+		} else if server != nil {
+			// register afresh and keep the token in memory
+			transport := client.HTTPClient.Transport
+			client = server.Client("")
+			client.HTTPClient.Transport = transport
+			client.Limiter = limiter
+			client.Debug = true
+			account = &Account{registration: registration_payload}
+			sign_up(ctx, client, account)
+		} else {
+			account = &Account{registration: registration_payload, token_file: CALLSIGN + ".token"}
+			// a recorded session has to start with the calls its replay makes
+			if *record == "" {
+				account.status_file = CALLSIGN + ".status.json"
+			}
+
+			// Check if an auth token file is present for the CALLSIGN provided
+			if !DoesAuthFileExist(CALLSIGN) {
+				fmt.Println("RegisterAgent")
+				sign_up(ctx, client, account)
+			} else {
+				token, err := read_auth_token_from_file(CALLSIGN)
+				check(err)
+				client.Token = token
+
+				// tokens only last until the universe is next reset, then the agent starts afresh
+				reset_date, err := account.reset_since(ctx, client)
+				if err != nil {
+					fmt.Println("[ERROR] checking for a server reset: " + err.Error())
+				}
+				if reset_date != "" {
+					fmt.Println("[INFO] The server has been reset since " + CALLSIGN + " signed up, starting afresh")
+					if err := account.start_over(ctx, client, reset_date, state_file, ledger_file); err != nil {
+						fmt.Println("[ERROR] " + err.Error())
+						os.Exit(1)
+					}
+				}
+			}
+		}
+
+		bot := NewBot(client, intel)
+		bot.account = account
+		for _, assignment := range role_assignments {
+			key, role, _ := strings.Cut(assignment, "=")
+			if err := bot.AssignRole(key, role); err != nil {
+				fmt.Println("[ERROR] -assign " + assignment + ": " + err.Error())
+				os.Exit(1)
+			}
+		}
+
+		if ledger_file != "" {
+			transaction_ledger, err := OpenLedger(ledger_file)
+			check(err)
+			defer transaction_ledger.Close()
+			bot.ledger = transaction_ledger
+		}
+
+		bot.state_file = state_file
+
+		fmt.Println("[INFO] Starting " + CALLSIGN)
+		restored, err := bot.LoadState(ctx)
+		if err != nil {
+			// a state file we cannot read is no worse than not having one
+			fmt.Println("[ERROR] loading state: " + err.Error())
+		}
+		if !restored {
+			check(bot.Bootstrap(ctx))
+			check(bot.SaveState())
+		}
+		// from here on the trade routes follow every market any agent looks at
+		bot.follow_intel()
+		bots = append(bots, bot)
+	}
+
+	// a recorded session only replays if the calls come in the same order every time, so those take turns
+	if *record != "" || *replay != "" {
+		bots[0].RunTurns(ctx)
+	} else {
+		var running sync.WaitGroup
+		for _, bot := range bots {
+			running.Add(1)
+			go func() {
+				defer running.Done()
+				for bot.Run(ctx) == ErrServerReset {
+					fresh, err := bot.start_over(ctx)
+					if err != nil {
+						fmt.Println("[ERROR] starting over after a server reset: " + err.Error())
+						return
+					}
+					bot = fresh
+				}
+			}()
+		}
+		running.Wait()
+	}
+	fmt.Println("[INFO] stopped")
+}
@@ -0,0 +1,355 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/ianlshaw/go-spacetrading/spacetraders"
+)
+
+// how much ore a refinery turns into produce at a time
+const refine_batch = 30
+
+// what the refinery module makes out of each ore
+var refinery_produce = map[spacetraders.TradeSymbol]spacetraders.TradeSymbol{
+	spacetraders.TradeSymbolIronOre:     spacetraders.TradeSymbolIron,
+	spacetraders.TradeSymbolCopperOre:   spacetraders.TradeSymbolCopper,
+	spacetraders.TradeSymbolAluminumOre: spacetraders.TradeSymbolAluminum,
+	spacetraders.TradeSymbolSilverOre:   spacetraders.TradeSymbolSilver,
+	spacetraders.TradeSymbolGoldOre:     spacetraders.TradeSymbolGold,
+	spacetraders.TradeSymbolPlatinumOre: spacetraders.TradeSymbolPlatinum,
+	spacetraders.TradeSymbolUraniteOre:  spacetraders.TradeSymbolUranite,
+	spacetraders.TradeSymbolMeritiumOre: spacetraders.TradeSymbolMeritium,
+}
+
+// every grade of the mounts and modules the miners care about
+var (
+	surveyor_mounts      = []spacetraders.MountSymbol{spacetraders.MountSymbolSurveyorI, spacetraders.MountSymbolSurveyorIi, spacetraders.MountSymbolSurveyorIii}
+	ore_refinery_modules = []spacetraders.ModuleSymbol{spacetraders.ModuleSymbolOreRefineryI}
+)
+
+// HasMount is true if the ship carries any one of mounts.
+func HasMount(ship spacetraders.Ship, mounts ...spacetraders.MountSymbol) bool {
+	for _, mount := range ship.Mounts {
+		if slices.Contains(mounts, mount.Symbol) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasModule is true if the ship is fitted with any one of modules.
+func HasModule(ship spacetraders.Ship, modules ...spacetraders.ModuleSymbol) bool {
+	for _, module := range ship.Modules {
+		if slices.Contains(modules, module.Symbol) {
+			return true
+		}
+	}
+	return false
+}
+
+func market_lists(exchanges []spacetraders.Exchange, trade_good_symbol spacetraders.TradeSymbol) bool {
+	for _, exchange := range exchanges {
+		if exchange.Symbol == trade_good_symbol {
+			return true
+		}
+	}
+	return false
+}
+
+func MarketBuys(market spacetraders.Market, trade_good_symbol spacetraders.TradeSymbol) bool {
+	return market_lists(market.Imports, trade_good_symbol) || market_lists(market.Exchange, trade_good_symbol)
+}
+
+func MarketSells(market spacetraders.Market, trade_good_symbol spacetraders.TradeSymbol) bool {
+	return market_lists(market.Exports, trade_good_symbol) || market_lists(market.Exchange, trade_good_symbol)
+}
+
+// BestKnownBuyer returns the market which pays the most for trade_good_symbol. Markets a satellite has
+// reported prices for win, failing that it is any market which lists the good as an import or exchange.
+func BestKnownBuyer(markets map[string]spacetraders.Market, trade_good_symbol spacetraders.TradeSymbol) (waypoint_symbol string, sell_price int64, found bool) {
+	waypoint_symbols := make([]string, 0, len(markets))
+	for k := range markets {
+		waypoint_symbols = append(waypoint_symbols, k)
+	}
+	sort.Strings(waypoint_symbols)
+
+	for _, symbol := range waypoint_symbols {
+		market := markets[symbol]
+		if !MarketBuys(market, trade_good_symbol) {
+			continue
+		}
+		var price int64
+		for _, trade_good := range market.TradeGoods {
+			if trade_good.Symbol == trade_good_symbol {
+				price = trade_good.SellPrice
+			}
+		}
+		if !found || price > sell_price {
+			waypoint_symbol = symbol
+			sell_price = price
+			found = true
+		}
+	}
+	return waypoint_symbol, sell_price, found
+}
+
+func (bot *Bot) best_known_buyer(trade_good_symbol spacetraders.TradeSymbol) (waypoint_symbol string, sell_price int64, found bool) {
+	return BestKnownBuyer(bot.intel.MarketsIn(bot.system_symbol), trade_good_symbol)
+}
+
+// is_worthless is true for cargo no known market will buy, unless the ship can refine it into something which sells.
+func (bot *Bot) is_worthless(ship spacetraders.Ship, trade_good_symbol spacetraders.TradeSymbol) bool {
+	if _, _, found := bot.best_known_buyer(trade_good_symbol); found {
+		return false
+	}
+	if produce, refinable := refinery_produce[trade_good_symbol]; refinable && HasModule(ship, ore_refinery_modules...) {
+		_, _, found := bot.best_known_buyer(produce)
+		return !found
+	}
+	return true
+}
+
+// usable_survey picks the unexpired survey with the most deposits worth selling.
+func (bot *Bot) usable_survey(ship spacetraders.Ship, now time.Time) (spacetraders.Survey, bool) {
+	bot.mu.Lock()
+	surveys := append([]spacetraders.Survey(nil), bot.surveys...)
+	bot.mu.Unlock()
+
+	best := spacetraders.Survey{}
+	best_score := 0
+	for _, survey := range surveys {
+		if survey.Symbol != ship.Nav.WaypointSymbol {
+			continue
+		}
+		if !survey.Expiration.IsZero() && !now.Before(survey.Expiration.Time) {
+			continue
+		}
+		score := 0
+		for _, deposit := range survey.Deposits {
+			if !bot.is_worthless(ship, deposit.Symbol) {
+				score++
+			}
+		}
+		if score > best_score {
+			best = survey
+			best_score = score
+		}
+	}
+	return best, best_score > 0
+}
+
+func (bot *Bot) forget_survey(signature string) {
+	bot.mu.Lock()
+	defer bot.mu.Unlock()
+	surveys := []spacetraders.Survey{}
+	for _, survey := range bot.surveys {
+		if survey.Signature != signature {
+			surveys = append(surveys, survey)
+		}
+	}
+	bot.surveys = surveys
+}
+
+// find_mining_waypoint picks the first asteroid in the system, engineered asteroids first as they sit near
+// the markets. It is empty if the system has none.
+func (bot *Bot) find_mining_waypoint(ctx context.Context) (string, error) {
+	bot.mu.Lock()
+	mining_waypoint := bot.mining_waypoint
+	bot.mu.Unlock()
+	if mining_waypoint != "" {
+		return mining_waypoint, nil
+	}
+	for _, waypoint_type := range []spacetraders.WaypointType{spacetraders.WaypointTypeEngineeredAsteroid, spacetraders.WaypointTypeAsteroid} {
+		asteroids, err := bot.client.ListWaypointsInSystemByType(ctx, bot.system_symbol, waypoint_type)
+		if err != nil {
+			return "", err
+		}
+		if len(asteroids) > 0 {
+			bot.mu.Lock()
+			bot.mining_waypoint = asteroids[0].Symbol
+			bot.mu.Unlock()
+			fmt.Println("[INFO] Mining at " + asteroids[0].Symbol)
+			return asteroids[0].Symbol, nil
+		}
+	}
+	return "", nil
+}
+
+// ApplyRoleMiner extracts ore at the system's asteroid until the hold is full, then takes it to whichever
+// market pays best. Anything no market wants is jettisoned straight away so it does not take up space.
+func (bot *Bot) ApplyRoleMiner(ctx context.Context, ship spacetraders.Ship) error {
+	fmt.Println("[INFO] " + ship.Symbol)
+
+	if ship.Nav.Status == spacetraders.NavStatusInTransit {
+		bot.print_in_transit(ship)
+		return nil
+	}
+	ctx = with_ledger_route(ctx, "MINING")
+
+	mining_waypoint, err := bot.find_mining_waypoint(ctx)
+	if err != nil {
+		return err
+	}
+	if mining_waypoint == "" {
+		fmt.Println("[INFO] No asteroid to mine in " + bot.system_symbol)
+		return nil
+	}
+
+	for _, item := range ship.Cargo.Inventory {
+		if bot.is_worthless(ship, item.Symbol) {
+			fmt.Print("[INFO] Jettisoning " + item.Symbol + " ")
+			fmt.Println(item.Units)
+			jettisoned, err := bot.client.Jettison(ctx, ship.Symbol, item.Symbol, item.Units)
+			if err != nil {
+				return err
+			}
+			ship.Cargo = jettisoned.Cargo
+		}
+	}
+
+	at_asteroid := IsShipAlreadyAtWaypoint(ship, mining_waypoint)
+	cooldown := ship.Cooldown.Remaining(bot.client.Now())
+	cooling_down := cooldown > 0
+
+	// refining shrinks the ore so it goes before deciding whether the hold is full
+	if at_asteroid && !cooling_down && HasModule(ship, ore_refinery_modules...) {
+		for _, item := range ship.Cargo.Inventory {
+			produce, refinable := refinery_produce[item.Symbol]
+			if !refinable || item.Units < refine_batch || bot.is_worthless(ship, produce) {
+				continue
+			}
+			if IsShipDocked(ship) {
+				if _, err := bot.client.OrbitShip(ctx, ship.Symbol); err != nil {
+					return err
+				}
+			}
+			fmt.Println("[INFO] Refining " + item.Symbol + " into " + produce)
+			_, err := bot.client.RefineShip(ctx, ship.Symbol, produce)
+			return err
+		}
+	}
+
+	if ship.Cargo.Units >= ship.Cargo.Capacity || (!is_ship_cargo_empty(ship) && !at_asteroid) {
+		return bot.sell_cargo(ctx, ship)
+	}
+
+	if !at_asteroid {
+		fmt.Println("[INFO] Heading to " + mining_waypoint + " to mine")
+		return bot.depart(ctx, ship, mining_waypoint)
+	}
+
+	if cooling_down {
+		fmt.Println("[DEBUG] Cooling down for " + cooldown.Round(time.Second).String())
+		return nil
+	}
+
+	if IsShipDocked(ship) {
+		if _, err := bot.client.OrbitShip(ctx, ship.Symbol); err != nil {
+			return err
+		}
+	}
+
+	survey, surveyed := bot.usable_survey(ship, bot.client.Now())
+	if !surveyed && HasMount(ship, surveyor_mounts...) {
+		created, err := bot.client.CreateSurvey(ctx, ship.Symbol)
+		if err != nil {
+			return err
+		}
+		fmt.Print("[INFO] Surveyed " + mining_waypoint + ", surveys: ")
+		fmt.Println(len(created.Surveys))
+		bot.mu.Lock()
+		bot.surveys = append(bot.surveys, created.Surveys...)
+		bot.mu.Unlock()
+		return nil
+	}
+
+	var extracted spacetraders.ExtractResourcesResponse
+	if surveyed {
+		extracted, err = bot.client.ExtractResourcesWithSurvey(ctx, ship.Symbol, survey)
+		if spacetraders.IsAPIErrorCode(err, spacetraders.ErrorCodeShipSurveyInvalid) ||
+			spacetraders.IsAPIErrorCode(err, spacetraders.ErrorCodeShipSurveyExpired) ||
+			spacetraders.IsAPIErrorCode(err, spacetraders.ErrorCodeShipSurveyExhausted) {
+			fmt.Println("[INFO] Survey " + survey.Signature + " is used up")
+			bot.forget_survey(survey.Signature)
+			return nil
+		}
+	} else {
+		extracted, err = bot.client.ExtractResources(ctx, ship.Symbol)
+	}
+	if spacetraders.IsAPIErrorCode(err, spacetraders.ErrorCodeCooldownConflict) {
+		fmt.Println("[INFO] " + ship.Symbol + " is still cooling down")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	yield := extracted.Extraction.Yield
+	fmt.Print("[INFO] Extracted " + yield.Symbol + " ")
+	fmt.Println(yield.Units)
+
+	if bot.is_worthless(ship, yield.Symbol) {
+		fmt.Println("[INFO] Jettisoning " + yield.Symbol + ", no market buys it")
+		_, err := bot.client.Jettison(ctx, ship.Symbol, yield.Symbol, yield.Units)
+		return err
+	}
+	return nil
+}
+
+// sell_cargo takes the biggest stack in the hold to the market which pays best for it, and sells
+// everything else that market buys while docked there.
+func (bot *Bot) sell_cargo(ctx context.Context, ship spacetraders.Ship) error {
+	var buyer string
+	var biggest int64
+	for _, item := range ship.Cargo.Inventory {
+		waypoint_symbol, _, found := bot.best_known_buyer(item.Symbol)
+		if found && item.Units > biggest {
+			buyer = waypoint_symbol
+			biggest = item.Units
+		}
+	}
+	if buyer == "" {
+		fmt.Println("[INFO] No known market buys any of the cargo")
+		return nil
+	}
+
+	if !IsShipAlreadyAtWaypoint(ship, buyer) {
+		fmt.Println("[INFO] Taking cargo to " + buyer + " to sell")
+		return bot.depart(ctx, ship, buyer)
+	}
+
+	if !IsShipDocked(ship) {
+		docked, err := bot.client.DockShip(ctx, ship.Symbol)
+		if err != nil {
+			return ignore_in_transit(ship.Symbol, err)
+		}
+		ship.Nav = docked.Nav
+	}
+
+	market, _ := bot.market(buyer)
+	for _, item := range ship.Cargo.Inventory {
+		if !MarketBuys(market, item.Symbol) {
+			continue
+		}
+		var trade_volume int64
+		for _, trade_good := range market.TradeGoods {
+			if trade_good.Symbol == item.Symbol {
+				trade_volume = trade_good.TradeVolume
+			}
+		}
+		if err := bot.sell_units(ctx, ship.Symbol, item.Symbol, item.Units, trade_volume); err != nil {
+			return err
+		}
+	}
+
+	if ship.Fuel.Capacity > 0 && MarketSells(market, spacetraders.TradeSymbolFuel) {
+		if _, err := bot.client.RefuelShip(ctx, ship.Symbol); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ianlshaw/go-spacetrading/spacetraders"
+)
+
+// how many jumps away from a ship's system the jump gate network is charted when looking for a route
+const jump_search_depth = 5
+
+// a BURN has to leave at least this share of the tank behind
+const burn_fuel_reserve = 0.5
+
+// and save at least this much time over CRUISE to be worth the fuel
+const burn_min_saving = time.Minute
+
+// ChooseFlightMode picks how to fly a leg of distance. BURN when the tank can pay double and still keep
+// a reserve, and the leg is long enough for the time saved to matter. DRIFT when the tank cannot
+// cover the leg even at CRUISE, which is slow but always gets there. CRUISE otherwise.
+func ChooseFlightMode(ship spacetraders.Ship, distance float64) spacetraders.FlightMode {
+	// ships without a tank, like probes, fly for free
+	if ship.Fuel.Capacity == 0 {
+		return spacetraders.FlightModeCruise
+	}
+	if spacetraders.FuelCost(distance, spacetraders.FlightModeCruise) > ship.Fuel.Current {
+		return spacetraders.FlightModeDrift
+	}
+	fuel_left_after_burn := ship.Fuel.Current - spacetraders.FuelCost(distance, spacetraders.FlightModeBurn)
+	time_saved := spacetraders.TravelTime(distance, ship.Engine.Speed, spacetraders.FlightModeCruise) - spacetraders.TravelTime(distance, ship.Engine.Speed, spacetraders.FlightModeBurn)
+	if float64(fuel_left_after_burn) >= burn_fuel_reserve*float64(ship.Fuel.Capacity) && time_saved >= burn_min_saving {
+		return spacetraders.FlightModeBurn
+	}
+	return spacetraders.FlightModeCruise
+}
+
+// waypoint looks a waypoint up, asking the game only the first time.
+func (bot *Bot) waypoint(ctx context.Context, waypoint_symbol string) (spacetraders.Waypoint, error) {
+	bot.mu.Lock()
+	waypoint, ok := bot.waypoints[waypoint_symbol]
+	bot.mu.Unlock()
+	if ok {
+		return waypoint, nil
+	}
+	waypoint, err := bot.client.GetWaypoint(ctx, spacetraders.SystemSymbolOf(waypoint_symbol), waypoint_symbol)
+	if err != nil {
+		return waypoint, err
+	}
+	bot.mu.Lock()
+	bot.waypoints[waypoint_symbol] = waypoint
+	bot.mu.Unlock()
+	return waypoint, nil
+}
+
+// depart sends the ship towards waypoint_symbol. Destinations in another system are reached one leg per
+// call: to the local jump gate, then a jump at a time through the network. Within a system the ship
+// stops to refuel wherever the tank will not cover the way there, again one leg per call.
+func (bot *Bot) depart(ctx context.Context, ship spacetraders.Ship, waypoint_symbol string) error {
+	if destination_system := spacetraders.SystemSymbolOf(waypoint_symbol); destination_system != ship.Nav.SystemSymbol {
+		return bot.jump_towards(ctx, ship, destination_system)
+	}
+
+	if _, err := bot.waypoint(ctx, waypoint_symbol); err != nil {
+		return err
+	}
+	next_stop := waypoint_symbol
+	route, found := spacetraders.PlanRoute(ship, waypoint_symbol, bot.waypoints_in_system(ship), bot.fuel_stations())
+	if !found {
+		fmt.Println("[INFO] No refuel stops get " + ship.Symbol + " to " + waypoint_symbol + ", going direct")
+	} else if len(route) > 1 {
+		next_stop = route[0]
+		fmt.Println("[INFO] Refuelling at " + next_stop + " on the way to " + waypoint_symbol)
+	}
+
+	destination, err := bot.waypoint(ctx, next_stop)
+	if err != nil {
+		return err
+	}
+	here := ship.Nav.Route.Destination
+	distance := spacetraders.DistanceBetweenTwoCoordinates(here.X, here.Y, destination.X, destination.Y)
+
+	// fill up first if this leg needs more than is in the tank and fuel is sold here
+	if ship.Fuel.Current < spacetraders.FuelCost(distance, spacetraders.FlightModeCruise) && bot.sells_fuel(ship.Nav.WaypointSymbol) {
+		if !IsShipDocked(ship) {
+			docked, err := bot.client.DockShip(ctx, ship.Symbol)
+			if err != nil {
+				return ignore_in_transit(ship.Symbol, err)
+			}
+			ship.Nav = docked.Nav
+		}
+		refueled, err := bot.client.RefuelShip(ctx, ship.Symbol)
+		if err != nil {
+			return err
+		}
+		ship.Fuel = refueled.Fuel
+	}
+
+	if IsShipDocked(ship) {
+		if _, err := bot.client.OrbitShip(ctx, ship.Symbol); err != nil {
+			return err
+		}
+	}
+
+	if flight_mode := ChooseFlightMode(ship, distance); flight_mode != ship.Nav.FlightMode {
+		fmt.Println("[INFO] Switching to " + string(flight_mode) + " for " + next_stop)
+		if _, err := bot.client.PatchShipNav(ctx, ship.Symbol, flight_mode); err != nil {
+			return err
+		}
+	}
+
+	_, err = bot.client.NavigateShip(ctx, ship.Symbol, next_stop)
+	return ignore_in_transit(ship.Symbol, err)
+}
+
+// waypoints_in_system lists the known waypoints in the ship's system, including where it is now,
+// in a stable order so route planning always breaks ties the same way.
+func (bot *Bot) waypoints_in_system(ship spacetraders.Ship) []spacetraders.Waypoint {
+	here := ship.Nav.Route.Destination
+	waypoints := []spacetraders.Waypoint{{
+		SystemSymbol: here.SystemSymbol,
+		Symbol:       ship.Nav.WaypointSymbol,
+		Type:         here.Type,
+		X:            here.X,
+		Y:            here.Y,
+	}}
+	bot.mu.Lock()
+	defer bot.mu.Unlock()
+	symbols := []string{}
+	for symbol, waypoint := range bot.waypoints {
+		if waypoint.SystemSymbol == ship.Nav.SystemSymbol && symbol != ship.Nav.WaypointSymbol {
+			symbols = append(symbols, symbol)
+		}
+	}
+	sort.Strings(symbols)
+	for _, symbol := range symbols {
+		waypoints = append(waypoints, bot.waypoints[symbol])
+	}
+	return waypoints
+}
+
+func (bot *Bot) sells_fuel(waypoint_symbol string) bool {
+	market, ok := bot.market(waypoint_symbol)
+	return ok && MarketSells(market, spacetraders.TradeSymbolFuel)
+}
+
+// fuel_stations is every known market which sells FUEL.
+func (bot *Bot) fuel_stations() map[string]bool {
+	stations := make(map[string]bool)
+	for symbol, market := range bot.intel.MarketsIn(bot.system_symbol) {
+		if MarketSells(market, spacetraders.TradeSymbolFuel) {
+			stations[symbol] = true
+		}
+	}
+	return stations
+}
+
+// jump_towards takes the ship one step along the shortest known jump gate route to system_symbol.
+func (bot *Bot) jump_towards(ctx context.Context, ship spacetraders.Ship, system_symbol string) error {
+	route, found := bot.graph.Route(ship.Nav.SystemSymbol, system_symbol)
+	if !found {
+		if err := bot.client.ExploreJumpGates(ctx, bot.graph, ship.Nav.SystemSymbol, jump_search_depth); err != nil {
+			return err
+		}
+		route, found = bot.graph.Route(ship.Nav.SystemSymbol, system_symbol)
+	}
+	if !found {
+		fmt.Println("[INFO] No known jump gate route from " + ship.Nav.SystemSymbol + " to " + system_symbol)
+		return nil
+	}
+
+	gate := route[0]
+	if !IsShipAlreadyAtWaypoint(ship, gate) {
+		fmt.Println("[INFO] Heading to jump gate " + gate)
+		return bot.depart(ctx, ship, gate)
+	}
+
+	if cooldown := ship.Cooldown.Remaining(bot.client.Now()); cooldown > 0 {
+		fmt.Println("[DEBUG] Waiting to jump, cooling down for " + cooldown.Round(time.Second).String())
+		return nil
+	}
+	if IsShipDocked(ship) {
+		if _, err := bot.client.OrbitShip(ctx, ship.Symbol); err != nil {
+			return err
+		}
+	}
+	fmt.Println("[INFO] Jumping " + strings.Join(route, " -> "))
+	jumped, err := bot.client.JumpShip(ctx, ship.Symbol, route[1])
+	if err != nil {
+		return ignore_in_transit(ship.Symbol, err)
+	}
+	fmt.Println("[INFO] Arrived in " + jumped.Nav.SystemSymbol)
+	return nil
+}
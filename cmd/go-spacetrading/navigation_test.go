@@ -0,0 +1,39 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/ianlshaw/go-spacetrading/spacetraders"
+)
+
+func TestChooseFlightMode(t *testing.T) {
+	ship := func(fuel int64, capacity int64, speed int64) spacetraders.Ship {
+		ship := spacetraders.Ship{}
+		ship.Fuel.Current = fuel
+		ship.Fuel.Capacity = capacity
+		ship.Engine.Speed = speed
+		return ship
+	}
+	tests := []struct {
+		name     string
+		ship     spacetraders.Ship
+		distance float64
+		want     spacetraders.FlightMode
+	}{
+		{"probes have no tank", ship(0, 0, 3), 500, spacetraders.FlightModeCruise},
+		{"too little fuel to cruise", ship(10, 400, 10), 50, spacetraders.FlightModeDrift},
+		{"just enough fuel to cruise", ship(50, 400, 10), 50, spacetraders.FlightModeCruise},
+		// 265s at CRUISE against 140s at BURN, leaving 200 of 400
+		{"long leg with fuel to spare", ship(400, 400, 10), 100, spacetraders.FlightModeBurn},
+		{"burning would eat into the reserve", ship(300, 400, 10), 100, spacetraders.FlightModeCruise},
+		// 98s at CRUISE against 57s at BURN
+		{"fast ship saves too little", ship(400, 400, 30), 100, spacetraders.FlightModeCruise},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := ChooseFlightMode(test.ship, test.distance); got != test.want {
+				t.Errorf("ChooseFlightMode over %.0f = %s, want %s", test.distance, got, test.want)
+			}
+		})
+	}
+}
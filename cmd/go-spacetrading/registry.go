@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ianlshaw/go-spacetrading/spacetraders"
+)
+
+// Role is a behaviour a ship can be given. Play is called every time the ship wakes and takes it one
+// step further, it should return quickly rather than wait for the ship to arrive anywhere.
+type Role interface {
+	Play(ctx context.Context, ship spacetraders.Ship) error
+}
+
+// RoleFunc lets a plain function be used as a Role.
+type RoleFunc func(ctx context.Context, ship spacetraders.Ship) error
+
+func (f RoleFunc) Play(ctx context.Context, ship spacetraders.Ship) error {
+	return f(ctx, ship)
+}
+
+// the roles every bot starts with
+const (
+	RoleTrader        = "TRADER"
+	RoleMarketWatcher = "MARKET_WATCHER"
+	RoleContractor    = "CONTRACTOR"
+	RoleMiner         = "MINER"
+	RoleExplorer      = "EXPLORER"
+	RoleIdle          = "IDLE"
+)
+
+// which role a ship plays when nothing has been assigned to it, by the role the game registered it with
+var default_assignments = map[spacetraders.ShipRole]string{
+	spacetraders.ShipRoleCommand:   RoleTrader,
+	spacetraders.ShipRoleSatellite: RoleMarketWatcher,
+	spacetraders.ShipRoleHauler:    RoleContractor,
+	spacetraders.ShipRoleExcavator: RoleMiner,
+	spacetraders.ShipRoleExplorer:  RoleExplorer,
+}
+
+func (bot *Bot) register_default_roles() {
+	bot.RegisterRole(RoleTrader, RoleFunc(bot.ApplyRoleCommand))
+	bot.RegisterRole(RoleMarketWatcher, RoleFunc(bot.ApplyRoleSatellite))
+	bot.RegisterRole(RoleContractor, RoleFunc(bot.ApplyRoleContract))
+	bot.RegisterRole(RoleMiner, RoleFunc(bot.ApplyRoleMiner))
+	bot.RegisterRole(RoleExplorer, RoleFunc(bot.ApplyRoleExplorer))
+	bot.RegisterRole(RoleIdle, RoleFunc(func(ctx context.Context, ship spacetraders.Ship) error { return nil }))
+}
+
+// RegisterRole makes role available to be assigned under name, replacing any role already called that.
+func (bot *Bot) RegisterRole(name string, role Role) {
+	bot.mu.Lock()
+	defer bot.mu.Unlock()
+	bot.roles[name] = role
+}
+
+// AssignRole has every ship matching key play the role registered as name. key is either a ship symbol
+// or a role the game registers ships with, such as HAULER; a ship's own symbol wins over its registration.
+func (bot *Bot) AssignRole(key string, name string) error {
+	bot.mu.Lock()
+	defer bot.mu.Unlock()
+	if _, ok := bot.roles[name]; !ok {
+		return fmt.Errorf("no role called %s, there is %s", name, strings.Join(bot.role_names(), ", "))
+	}
+	bot.assignments[key] = name
+	return nil
+}
+
+// role_names lists the registered roles, sorted. bot.mu must be held.
+func (bot *Bot) role_names() []string {
+	names := make([]string, 0, len(bot.roles))
+	for name := range bot.roles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RoleName is the name of the role the ship plays, empty if it has none.
+func (bot *Bot) RoleName(ship spacetraders.Ship) string {
+	bot.mu.Lock()
+	defer bot.mu.Unlock()
+	if name, ok := bot.assignments[ship.Symbol]; ok {
+		return name
+	}
+	if name, ok := bot.assignments[string(ship.Registration.Role)]; ok {
+		return name
+	}
+	return default_assignments[ship.Registration.Role]
+}
+
+// ships_with_role picks out the ships playing the role called name.
+func (bot *Bot) ships_with_role(ships []spacetraders.Ship, name string) []spacetraders.Ship {
+	matching := []spacetraders.Ship{}
+	for _, ship := range ships {
+		if bot.RoleName(ship) == name {
+			matching = append(matching, ship)
+		}
+	}
+	return matching
+}
+
+// PlayShip gives the ship its turn at whichever role it has been assigned.
+func (bot *Bot) PlayShip(ctx context.Context, ship spacetraders.Ship) error {
+	name := bot.RoleName(ship)
+	bot.mu.Lock()
+	role, ok := bot.roles[name]
+	bot.mu.Unlock()
+	if !ok {
+		fmt.Println("[INFO] " + ship.Symbol + " has no role, registered as " + string(ship.Registration.Role))
+		return nil
+	}
+	return role.Play(with_ledger_ship(ctx, ship.Symbol, name), ship)
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ianlshaw/go-spacetrading/spacetraders"
+)
+
+// ErrServerReset is what Run stops with when the game turns the agent's token down, as it does every
+// token once the universe has been reset.
+var ErrServerReset = errors.New("the server has been reset")
+
+// files from before a server reset are moved to a directory in here named after the reset they belong to
+const archive_directory = "archive"
+
+// Account is how an agent was signed up, so it can be signed up again after a server reset.
+type Account struct {
+	registration spacetraders.RegisterAgentPayload
+
+	// where the agent's token and the status of the server it signed up with are kept, empty to only
+	// keep the token in memory and not watch the reset date
+	token_file  string
+	status_file string
+}
+
+// sign_up registers the agent, has client use its token and keeps the token and server status in their files.
+func (account *Account) sign_up(ctx context.Context, client *spacetraders.Client) error {
+	registration, err := client.RegisterAgent(ctx, account.registration)
+	if err != nil {
+		return err
+	}
+	client.Token = registration.Token
+	if account.token_file != "" {
+		if err := WriteAuthTokenToFile(registration.Token, account.token_file); err != nil {
+			return err
+		}
+	}
+	if account.status_file == "" {
+		return nil
+	}
+	status, err := client.GetStatus(ctx)
+	if err != nil {
+		return err
+	}
+	return save_server_status(account.status_file, status)
+}
+
+// reset_since returns the reset date the agent signed up after if the server has been reset since,
+// empty if it has not. Either the server's reset date has moved on, or the game turns the token down.
+func (account *Account) reset_since(ctx context.Context, client *spacetraders.Client) (string, error) {
+	if account.status_file == "" {
+		return "", nil
+	}
+	status, err := client.GetStatus(ctx)
+	if err != nil {
+		return "", err
+	}
+	saved, found, err := load_server_status(account.status_file)
+	if err != nil {
+		return "", err
+	}
+	if found && saved.ResetDate != status.ResetDate {
+		return saved.ResetDate, nil
+	}
+
+	// a token kept from before its server status was does not say which reset it is from
+	_, err = client.GetAgent(ctx)
+	if spacetraders.IsUnauthorized(err) {
+		return account.reset_date(), nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "", save_server_status(account.status_file, status)
+	}
+	return "", nil
+}
+
+// start_over moves the agent's token, server status and files out of the way to the archive for
+// reset_date and signs the agent up again. It can be tried again if signing up fails.
+func (account *Account) start_over(ctx context.Context, client *spacetraders.Client, reset_date string, files ...string) error {
+	files = append([]string{account.token_file, account.status_file}, files...)
+	if err := archive_files(reset_date, files...); err != nil {
+		return err
+	}
+	return account.sign_up(ctx, client)
+}
+
+// reset_date is the reset the agent's files belong to, going by the saved server status or failing that
+// the day the token was written.
+func (account *Account) reset_date() string {
+	if saved, found, err := load_server_status(account.status_file); err == nil && found {
+		return saved.ResetDate
+	}
+	return date_written(account.token_file)
+}
+
+func load_server_status(filename string) (status spacetraders.ServerStatus, found bool, err error) {
+	if filename == "" {
+		return status, false, nil
+	}
+	contents, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return status, false, nil
+	}
+	if err != nil {
+		return status, false, err
+	}
+	if err := json.Unmarshal(contents, &status); err != nil {
+		return status, false, fmt.Errorf("%s: %w", filename, err)
+	}
+	return status, true, nil
+}
+
+func save_server_status(filename string, status spacetraders.ServerStatus) error {
+	contents, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return err
+	}
+	return write_file_atomically(filename, contents)
+}
+
+// date_written is the day filename was last written, "unknown" if there is no such file.
+func date_written(filename string) string {
+	info, err := os.Stat(filename)
+	if filename == "" || err != nil {
+		return "unknown"
+	}
+	return info.ModTime().UTC().Format(time.DateOnly)
+}
+
+// archive_files moves every one of filenames which exists into the archive for reset_date.
+func archive_files(reset_date string, filenames ...string) error {
+	directory := filepath.Join(archive_directory, reset_date)
+	for _, filename := range filenames {
+		if filename == "" {
+			continue
+		}
+		if _, err := os.Stat(filename); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err := os.MkdirAll(directory, 0755); err != nil {
+			return err
+		}
+		archived := filepath.Join(directory, filepath.Base(filename))
+		if err := os.Rename(filename, archived); err != nil {
+			return err
+		}
+		fmt.Println("[INFO] archived " + filename + " to " + archived)
+	}
+	return nil
+}
+
+// start_over signs the agent up again after a server reset and bootstraps a bot set up as this one
+// was to play it, archiving the old state and ledger. Until the game takes the registration, which it
+// may not straight after a reset, it tries again every turn.
+func (bot *Bot) start_over(ctx context.Context) (*Bot, error) {
+	reset_date := bot.account.reset_date()
+	if bot.unsubscribe != nil {
+		bot.unsubscribe()
+	}
+	bot.ledger.Close()
+	for {
+		err := bot.account.start_over(ctx, bot.client, reset_date, bot.state_file, bot.ledger.filename)
+		if err == nil {
+			break
+		}
+		fmt.Println("[ERROR] signing up again: " + err.Error())
+		sleep(ctx, time.Duration(turn_length)*time.Second)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+
+	fresh := NewBot(bot.client, bot.intel)
+	fresh.account = bot.account
+	fresh.state_file = bot.state_file
+	// roles registered on top of the defaults carry over along with whoever was assigned them. The
+	// defaults are the fresh bot's own, the old ones would play its ships on the old bot
+	bot.mu.Lock()
+	for name, role := range bot.roles {
+		if _, ok := fresh.roles[name]; !ok {
+			fresh.roles[name] = role
+		}
+	}
+	for key, role := range bot.assignments {
+		fresh.assignments[key] = role
+	}
+	bot.mu.Unlock()
+	if bot.ledger.filename != "" {
+		ledger, err := OpenLedger(bot.ledger.filename)
+		if err != nil {
+			return nil, err
+		}
+		fresh.ledger = ledger
+	}
+
+	// whatever was known about markets went with the old universe
+	status, err := fresh.client.GetStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fresh.intel.NewUniverse(status.ResetDate)
+	if err := fresh.Bootstrap(ctx); err != nil {
+		return nil, err
+	}
+	if err := fresh.SaveState(); err != nil {
+		return nil, err
+	}
+	fresh.follow_intel()
+	return fresh, nil
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ianlshaw/go-spacetrading/spacetraders"
+)
+
+func IsASatelliteDockedAtMarketplace(list_ships_result []spacetraders.Ship, waypoint_symbol string) (answer bool) {
+	for _, ship := range list_ships_result {
+		if ship.Registration.Role == spacetraders.ShipRoleSatellite {
+			if ship.Nav.WaypointSymbol == waypoint_symbol {
+				if ship.Nav.Status == spacetraders.NavStatusDocked {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func IsShipAlreadyAtWaypoint(ship_to_test spacetraders.Ship, waypoint_symbol string) bool {
+	return (ship_to_test.Nav.WaypointSymbol == waypoint_symbol && ship_to_test.Nav.Status != spacetraders.NavStatusInTransit)
+}
+
+func IsShipDocked(ship spacetraders.Ship) bool {
+	return ship.Nav.Status == spacetraders.NavStatusDocked
+}
+
+// print_in_transit says where the ship is headed and how long until it gets there.
+func (bot *Bot) print_in_transit(ship spacetraders.Ship) {
+	fmt.Println("[DEBUG] IN_TRANSIT TO " + ship.Nav.Route.Destination.Symbol)
+	fmt.Println("[DEBUG] Arrival in " + ship.TimeUntilArrival(bot.client.Now()).Round(time.Second).String())
+}
+
+func is_ship_cargo_empty(ship spacetraders.Ship) bool {
+	return ship.Cargo.Units == 0
+}
+
+// ignore_in_transit swallows the error the game returns when an action is attempted on a ship which
+// is still travelling, the ship will get another go once it has arrived.
+func ignore_in_transit(ship_symbol string, err error) error {
+	if spacetraders.IsAPIErrorCode(err, spacetraders.ErrorCodeShipInTransit) {
+		fmt.Println("[INFO] " + ship_symbol + " is still in transit")
+		return nil
+	}
+	return err
+}
+
+func (bot *Bot) ApplyRoleCommand(ctx context.Context, ship spacetraders.Ship) error {
+	fmt.Println("[INFO] " + ship.Symbol)
+
+	//fmt.Println("[DEBUG] ApplyRoleCommand")
+
+	if ship.Nav.Status == spacetraders.NavStatusInTransit {
+		bot.print_in_transit(ship)
+		return nil
+	}
+
+	//fmt.Print("[DEBUG] ship.Cargo.Inventory")
+	//fmt.Print(ship.Cargo.Inventory)
+	//fmt.Println()
+
+	// a trip under way is finished before anything else
+	if bot.trade_trip(ship.Symbol).State != TradeIdle {
+		return bot.trade(ctx, ship)
+	}
+
+	// count number of satellites
+	var number_of_satellites int
+
+	ship_list, err := bot.client.ListShips(ctx)
+	if err != nil {
+		return err
+	}
+
+	// TODO: not sure this needs to be here or exist
+	number_of_satellites = len(bot.ships_with_role(ship_list, RoleMarketWatcher))
+
+	// we need the X and Y coord of the command ship to figure out which shipyard is closest
+	current_waypoint, err := bot.client.GetWaypoint(ctx, bot.system_symbol, ship.Nav.WaypointSymbol)
+	if err != nil {
+		return err
+	}
+
+	bot.mu.Lock()
+	number_of_markets := len(bot.markets_to_cover)
+	bot.mu.Unlock()
+
+	if number_of_satellites < number_of_markets {
+		fmt.Println("[INFO] We need more satellites, boss")
+
+		best_distance := 99999999.9999999
+		var probe_ship_shipyard_waypoint_symbol string
+		for _, shipyard := range bot.probe_shipyards {
+			distance := spacetraders.DistanceBetweenTwoCoordinates(shipyard.X, shipyard.Y, current_waypoint.X, current_waypoint.Y)
+			if distance < best_distance {
+				best_distance = distance
+				probe_ship_shipyard_waypoint_symbol = shipyard.Symbol
+			}
+		}
+
+		fmt.Println("[DEBUG] buyer_ship_destination_symbol:")
+		fmt.Println(probe_ship_shipyard_waypoint_symbol)
+
+		fmt.Println("[DEBUG] command ship current location")
+		fmt.Println(ship.Nav.WaypointSymbol)
+
+		if IsShipAlreadyAtWaypoint(ship, probe_ship_shipyard_waypoint_symbol) {
+
+			if !IsShipDocked(ship) {
+				if _, err := bot.client.DockShip(ctx, ship.Symbol); err != nil {
+					return ignore_in_transit(ship.Symbol, err)
+				}
+			}
+
+			// This will only purchase one ship per turn. We can buy more per turn but we need to update the satellite count afterwards
+			purchase, err := bot.client.PurchaseShip(ctx, spacetraders.ShipTypeProbe, ship.Nav.WaypointSymbol)
+			if err != nil {
+				if spacetraders.IsAPIErrorCode(err, spacetraders.ErrorCodeInsufficientCredits) {
+					fmt.Println("[INFO] Not enough credits for a satellite yet")
+					return nil
+				}
+				return err
+			}
+			bot.start_purchased(purchase.Ship)
+
+			// TODO: buy satellites upto len(markets_to_cover)
+			fmt.Println("[INFO] command ship is at probe_ship_shipyard_waypoint_symbol BUY SATELLITES")
+
+		} else {
+			// TODO: send command ship to shipyard which sells satellites
+			if IsShipDocked(ship) {
+				if _, err := bot.client.OrbitShip(ctx, ship.Symbol); err != nil {
+					return err
+				}
+			}
+			if _, err := bot.client.NavigateShip(ctx, ship.Symbol, probe_ship_shipyard_waypoint_symbol); err != nil {
+				return ignore_in_transit(ship.Symbol, err)
+			}
+			fmt.Println("[INFO] Heading to " + probe_ship_shipyard_waypoint_symbol + " to buy satellites")
+		}
+	} else {
+		// we have enough satellites
+		market_watchers := bot.ships_with_role(ship_list, RoleMarketWatcher)
+		bot.mu.Lock()
+		AssignSatellitesToMarkets(market_watchers, bot.markets_to_cover)
+		bot.mu.Unlock()
+		return bot.trade(ctx, ship)
+	}
+	return nil
+}
+
+// sorted_market_symbols returns the keys of markets_to_cover in a stable order, so the same
+// state always produces the same sequence of calls.
+func sorted_market_symbols(markets_to_cover map[string]string) []string {
+	keys := make([]string, 0, len(markets_to_cover))
+
+	for k := range markets_to_cover {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// AssignSatellitesToMarkets hands out the ships watching markets to the markets without one.
+func AssignSatellitesToMarkets(list_of_ships []spacetraders.Ship, markets_to_cover map[string]string) {
+
+	// satellites already covering a market, possibly from a previous run, keep their market
+	already_assigned := make(map[string]bool)
+	for _, satellite := range markets_to_cover {
+		already_assigned[satellite] = true
+	}
+
+	list_of_satellites := []spacetraders.Ship{}
+
+	for _, ship := range list_of_ships {
+		if !already_assigned[ship.Symbol] {
+			list_of_satellites = append(list_of_satellites, ship)
+		}
+	}
+
+	var satellite_index int
+	for _, market_waypoint := range sorted_market_symbols(markets_to_cover) {
+		if satellite_index >= len(list_of_satellites) {
+			return
+		}
+		// if this market does not have a satellite assigned:
+		if markets_to_cover[market_waypoint] == "" {
+			markets_to_cover[market_waypoint] = list_of_satellites[satellite_index].Symbol
+			fmt.Println("[INFO] Assigned satellite " + list_of_satellites[satellite_index].Symbol + " to market " + market_waypoint)
+			satellite_index++
+		}
+	}
+}
+
+func (bot *Bot) ApplyRoleSatellite(ctx context.Context, ship spacetraders.Ship) error {
+	fmt.Println("[INFO] " + ship.Symbol)
+
+	if ship.Nav.Status == spacetraders.NavStatusInTransit {
+		bot.print_in_transit(ship)
+		fmt.Println()
+		return nil
+	}
+
+	var assigned_market_waypoint string
+
+	// find the name of this satellite as a value in the markets_to_cover map, return the key of that value as assigned_market_waypoint
+	bot.mu.Lock()
+	for market_symbol, assigned_satellite := range bot.markets_to_cover {
+		if ship.Symbol == assigned_satellite {
+			assigned_market_waypoint = market_symbol
+			break
+		}
+	}
+	bot.mu.Unlock()
+
+	if assigned_market_waypoint == "" {
+		fmt.Println("[INFO] No market assigned")
+		return nil
+	}
+
+	if IsShipAlreadyAtWaypoint(ship, assigned_market_waypoint) {
+		fmt.Println("[INFO] Already at assigned market waypoint")
+		if !IsShipDocked(ship) {
+			if _, err := bot.client.DockShip(ctx, ship.Symbol); err != nil {
+				return ignore_in_transit(ship.Symbol, err)
+			}
+		}
+		return bot.UpdateTradeRoutesIncludingThisWaypoint(ctx, assigned_market_waypoint)
+	} else {
+		fmt.Println("[INFO] Not at assigned market waypoint, heading there now")
+		return bot.depart(ctx, ship, assigned_market_waypoint)
+	}
+
+	// find my assignment waypoint
+	// am i there?
+	// dock and get market
+	// if no orbit and navigate there
+
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ianlshaw/go-spacetrading/spacetraders"
+)
+
+// how long a ship with nothing to wait for leaves it before taking another look
+const idle_wait = 30 * time.Second
+
+// arrivals and cooldowns are given to the second, wake a little after so the game agrees they are over
+const wake_margin = time.Second
+
+// a ship which got something done goes again straight away, up to this many times in a row
+const max_busy_runs = 10
+
+// NextWake is when the ship next has something to do: when it arrives or when its cooldown is over.
+// waiting is false if it is doing neither, and the wake is idle_wait from now.
+func NextWake(ship spacetraders.Ship, now time.Time) (wake time.Time, waiting bool) {
+	if until := ship.TimeUntilArrival(now); until > 0 {
+		return now.Add(until + wake_margin), true
+	}
+	if remaining := ship.Cooldown.Remaining(now); remaining > 0 {
+		return now.Add(remaining + wake_margin), true
+	}
+	return now.Add(idle_wait), false
+}
+
+// made_progress is true if the ship is somewhere, or carrying something, different to before its turn.
+func made_progress(before spacetraders.Ship, after spacetraders.Ship) bool {
+	return before.Nav.Status != after.Nav.Status ||
+		before.Nav.WaypointSymbol != after.Nav.WaypointSymbol ||
+		before.Cargo.Units != after.Cargo.Units ||
+		before.Fuel.Current != after.Fuel.Current
+}
+
+// Run gives every ship its own loop, waking it when it arrives somewhere or its cooldown ends, until
+// ctx is cancelled. Ships run concurrently and share the client's rate limiter. A ship the bot buys
+// starts straight away, and every turn_length the fleet is checked for any other new ships, the ledger
+// is reconciled and the state saved. If the bot can sign its
+// agent up again and the game turns its token down, Run stops every ship and returns ErrServerReset.
+func (bot *Bot) Run(ctx context.Context) error {
+	ctx, stop_ships := context.WithCancel(ctx)
+	defer stop_ships()
+	var wg sync.WaitGroup
+	running := make(map[string]bool)
+	reset := false
+	start := func(ship spacetraders.Ship) {
+		if running[ship.Symbol] {
+			return
+		}
+		running[ship.Symbol] = true
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			bot.run_ship(ctx, ship)
+		}()
+	}
+
+	for ctx.Err() == nil {
+		period_start := time.Now()
+
+		// ships carry on trading while the agent is fetched, so its credits only say anything about the
+		// ledger if nothing was recorded meanwhile. Holding the books means nothing is on its way either.
+		recorded := bot.ledger.Recorded()
+		agent, err := bot.client.GetAgent(ctx)
+		if err == nil {
+			bot.books.Lock()
+			if bot.ledger.Recorded() == recorded {
+				bot.ledger.Reconcile(agent.Credits)
+			} else {
+				fmt.Println("[INFO] Ships traded while the agent was fetched, reconciling the ledger next turn")
+			}
+			bot.books.Unlock()
+		}
+		if err != nil {
+			fmt.Println("[ERROR] " + err.Error())
+			// every token stops working once the universe is reset
+			if spacetraders.IsUnauthorized(err) && bot.account != nil {
+				fmt.Println("[INFO] " + bot.account.registration.Symbol + "'s token was turned down, the server has been reset")
+				reset = true
+				break
+			}
+		} else {
+			fmt.Print("[INFO] " + agent.Symbol + " Credits: ")
+			fmt.Println(agent.Credits)
+		}
+
+		ships, err := bot.client.ListShips(ctx)
+		if err != nil {
+			fmt.Println("[ERROR] could not list ships", err)
+		}
+		for _, ship := range ships {
+			start(ship)
+		}
+
+		turn_over := time.After(time.Duration(turn_length) * time.Second)
+	waiting:
+		for {
+			select {
+			case <-ctx.Done():
+				break waiting
+			case <-turn_over:
+				break waiting
+			case ship := <-bot.purchased:
+				fmt.Println("[INFO] Starting " + ship.Symbol + " straight after buying it")
+				start(ship)
+			}
+		}
+
+		bot.ledger.PrintReport()
+		if err := bot.SaveState(); err != nil {
+			fmt.Println("[ERROR] saving state: " + err.Error())
+		}
+		fmt.Print("[INFO] http calls: ")
+		fmt.Print(bot.client.Calls())
+		fmt.Print(" in ")
+		fmt.Print(time.Since(period_start).Round(time.Second))
+		fmt.Println()
+		bot.client.ResetCalls()
+	}
+	stop_ships()
+	wg.Wait()
+	if reset {
+		return ErrServerReset
+	}
+	return nil
+}
+
+// start_purchased hands a ship just bought to Run. If Run has fallen behind, the ship is left for
+// the next turn_length to find.
+func (bot *Bot) start_purchased(ship spacetraders.Ship) {
+	select {
+	case bot.purchased <- ship:
+	default:
+	}
+}
+
+// run_ship plays one ship's role each time it wakes, until ctx is cancelled.
+func (bot *Bot) run_ship(ctx context.Context, ship spacetraders.Ship) {
+	busy_runs := 0
+	for ctx.Err() == nil {
+		// the copy of the ship is from before the wait, by now it has got where it was going
+		if ship.Nav.Status == spacetraders.NavStatusInTransit && ship.TimeUntilArrival(bot.client.Now()) == 0 {
+			ship.Nav.Status = spacetraders.NavStatusInOrbit
+			ship.Nav.WaypointSymbol = ship.Nav.Route.Destination.Symbol
+		}
+		bot.books.RLock()
+		err := bot.PlayShip(ctx, ship)
+		bot.books.RUnlock()
+		if err != nil {
+			fmt.Println("[ERROR] " + ship.Symbol + ": " + err.Error())
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		// arrivals and cooldowns are on the game's clock
+		now := bot.client.Now()
+		wake := now.Add(idle_wait)
+		updated, err := bot.client.GetShip(ctx, ship.Symbol)
+		if err != nil {
+			fmt.Println("[ERROR] " + ship.Symbol + ": " + err.Error())
+		} else {
+			var waiting bool
+			wake, waiting = NextWake(updated, now)
+			if !waiting && made_progress(ship, updated) && busy_runs < max_busy_runs {
+				wake = now
+				busy_runs++
+			} else {
+				busy_runs = 0
+			}
+			ship = updated
+		}
+
+		wait := wake.Sub(bot.client.Now())
+		fmt.Println("[DEBUG] " + ship.Symbol + " sleeping for " + wait.Round(time.Second).String())
+		sleep(ctx, wait)
+	}
+}
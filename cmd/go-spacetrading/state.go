@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ianlshaw/go-spacetrading/spacetraders"
+)
+
+// bumped whenever BotState changes in a way older files cannot be read as
+const state_version = 1
+
+// BotState is everything Bootstrap works out, so a restart can carry on without asking the game again.
+type BotState struct {
+	Version        int                              `json:"version"`
+	SystemSymbol   string                           `json:"systemSymbol"`
+	MarketsToCover map[string]string                `json:"marketsToCover"`
+	ProbeShipyards []spacetraders.Waypoint          `json:"probeShipyards"`
+	TradeRoutes    []TradeRoute                     `json:"tradeRoutes"`
+	Trades         map[string]TradeTrip             `json:"trades,omitempty"`
+	Markets        map[string]spacetraders.Market   `json:"markets"`
+	MiningWaypoint string                           `json:"miningWaypoint,omitempty"`
+	Waypoints      map[string]spacetraders.Waypoint `json:"waypoints"`
+}
+
+func (bot *Bot) state() BotState {
+	return BotState{
+		Version:        state_version,
+		SystemSymbol:   bot.system_symbol,
+		MarketsToCover: bot.markets_to_cover,
+		ProbeShipyards: bot.probe_shipyards,
+		TradeRoutes:    bot.trade_routes,
+		Trades:         bot.trades,
+		MiningWaypoint: bot.mining_waypoint,
+		Waypoints:      bot.waypoints,
+	}
+}
+
+// SaveState writes the bot's state to bot.state_file. The file is replaced in one go, so a crash
+// part way through leaves the previous state rather than half of this one.
+func (bot *Bot) SaveState() error {
+	if bot.state_file == "" {
+		return nil
+	}
+	markets := bot.intel.MarketsIn(bot.system_symbol)
+	bot.mu.Lock()
+	state := bot.state()
+	state.Markets = markets
+	contents, err := json.MarshalIndent(state, "", "  ")
+	bot.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return write_file_atomically(bot.state_file, contents)
+}
+
+func write_file_atomically(filename string, contents []byte) error {
+	dir := filepath.Dir(filename)
+	temporary, err := os.CreateTemp(dir, filepath.Base(filename)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	// does nothing once the rename has happened
+	defer os.Remove(temporary.Name())
+
+	if _, err := temporary.Write(contents); err != nil {
+		temporary.Close()
+		return err
+	}
+	if err := temporary.Sync(); err != nil {
+		temporary.Close()
+		return err
+	}
+	if err := temporary.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(temporary.Name(), filename); err != nil {
+		return err
+	}
+
+	// make the rename itself survive a power cut, not every platform can sync a directory
+	if directory, err := os.Open(dir); err == nil {
+		directory.Sync()
+		directory.Close()
+	}
+	return nil
+}
+
+// LoadState picks up where the bot left off from bot.state_file, checking it against the fleet the game
+// says the agent has. restored is false if there was nothing usable to load and Bootstrap is needed.
+func (bot *Bot) LoadState(ctx context.Context) (restored bool, err error) {
+	if bot.state_file == "" {
+		return false, nil
+	}
+	contents, err := os.ReadFile(bot.state_file)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	state := BotState{}
+	if err := json.Unmarshal(contents, &state); err != nil {
+		return false, fmt.Errorf("%s: %w", bot.state_file, err)
+	}
+
+	ships, err := bot.client.ListShips(ctx)
+	if err != nil {
+		return false, err
+	}
+	if !ValidateState(&state, ships, bot.ships_with_role(ships, RoleMarketWatcher)) {
+		return false, nil
+	}
+
+	bot.system_symbol = state.SystemSymbol
+	bot.markets_to_cover = state.MarketsToCover
+	bot.probe_shipyards = state.ProbeShipyards
+	bot.trade_routes = state.TradeRoutes
+	bot.mining_waypoint = state.MiningWaypoint
+	if state.Trades != nil {
+		bot.trades = state.Trades
+	}
+	for _, market := range state.Markets {
+		bot.intel.Restore(market)
+	}
+	if state.Waypoints != nil {
+		bot.waypoints = state.Waypoints
+	}
+	fmt.Println("[INFO] Restored state from " + bot.state_file)
+	return true, nil
+}
+
+// ValidateState checks saved state still fits the fleet. Satellites which are no longer among the
+// market_watchers, or which are assigned to more than one market, lose their assignment so they get
+// handed out again, and trips of ships which have gone are dropped. It returns false if the state is
+// no use at all: from another version, for a system none of the ships are in, or without any trade routes.
+func ValidateState(state *BotState, ships []spacetraders.Ship, market_watchers []spacetraders.Ship) bool {
+	if state.Version != state_version {
+		fmt.Println("[INFO] Saved state is from another version, starting afresh")
+		return false
+	}
+	if len(state.TradeRoutes) == 0 || len(state.MarketsToCover) == 0 {
+		fmt.Println("[INFO] Saved state has no trade routes, starting afresh")
+		return false
+	}
+
+	satellites := make(map[string]bool)
+	for _, ship := range market_watchers {
+		satellites[ship.Symbol] = true
+	}
+	in_system := false
+	fleet := make(map[string]bool)
+	for _, ship := range ships {
+		fleet[ship.Symbol] = true
+		if ship.Nav.SystemSymbol == state.SystemSymbol {
+			in_system = true
+		}
+	}
+	if !in_system {
+		fmt.Println("[INFO] No ships left in " + state.SystemSymbol + ", starting afresh")
+		return false
+	}
+
+	assigned := make(map[string]bool)
+	for _, market_symbol := range sorted_market_symbols(state.MarketsToCover) {
+		satellite := state.MarketsToCover[market_symbol]
+		if satellite == "" {
+			continue
+		}
+		if !satellites[satellite] || assigned[satellite] {
+			fmt.Println("[INFO] Unassigning " + satellite + " from " + market_symbol)
+			state.MarketsToCover[market_symbol] = ""
+			continue
+		}
+		assigned[satellite] = true
+	}
+
+	for ship_symbol := range state.Trades {
+		if !fleet[ship_symbol] {
+			fmt.Println("[INFO] Forgetting the trip " + ship_symbol + " was on")
+			delete(state.Trades, ship_symbol)
+		}
+	}
+	return true
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/ianlshaw/go-spacetrading/spacetraders"
+)
+
+func TestValidateState(t *testing.T) {
+	ship := func(symbol string, system_symbol string) spacetraders.Ship {
+		ship := spacetraders.Ship{Symbol: symbol}
+		ship.Nav.SystemSymbol = system_symbol
+		return ship
+	}
+	fleet := []spacetraders.Ship{ship("TESTER-1", "X1"), ship("TESTER-2", "X1"), ship("TESTER-3", "X1")}
+	satellites := fleet[1:]
+
+	tests := []struct {
+		name    string
+		change  func(state *BotState)
+		ok      bool
+		markets map[string]string
+		trades  []string
+	}{
+		{"still fits", func(state *BotState) {}, true,
+			map[string]string{"X1-A": "TESTER-2", "X1-B": "TESTER-3", "X1-C": ""}, []string{"TESTER-1"}},
+		{"another version", func(state *BotState) { state.Version++ }, false, nil, nil},
+		{"no trade routes", func(state *BotState) { state.TradeRoutes = nil }, false, nil, nil},
+		{"no markets", func(state *BotState) { state.MarketsToCover = map[string]string{} }, false, nil, nil},
+		{"another system", func(state *BotState) { state.SystemSymbol = "X2" }, false, nil, nil},
+		{"satellite gone", func(state *BotState) { state.MarketsToCover["X1-B"] = "TESTER-9" }, true,
+			map[string]string{"X1-A": "TESTER-2", "X1-B": "", "X1-C": ""}, []string{"TESTER-1"}},
+		{"satellite at two markets", func(state *BotState) { state.MarketsToCover["X1-C"] = "TESTER-2" }, true,
+			map[string]string{"X1-A": "TESTER-2", "X1-B": "TESTER-3", "X1-C": ""}, []string{"TESTER-1"}},
+		{"trader gone", func(state *BotState) { state.Trades["TESTER-9"] = TradeTrip{} }, true,
+			map[string]string{"X1-A": "TESTER-2", "X1-B": "TESTER-3", "X1-C": ""}, []string{"TESTER-1"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			state := BotState{
+				Version:        state_version,
+				SystemSymbol:   "X1",
+				MarketsToCover: map[string]string{"X1-A": "TESTER-2", "X1-B": "TESTER-3", "X1-C": ""},
+				TradeRoutes:    []TradeRoute{{TradeGoodSymbol: spacetraders.TradeSymbolFood}},
+				Trades:         map[string]TradeTrip{"TESTER-1": {}},
+			}
+			test.change(&state)
+			if ok := ValidateState(&state, fleet, satellites); ok != test.ok {
+				t.Fatalf("ValidateState = %v, want %v", ok, test.ok)
+			}
+			if !test.ok {
+				return
+			}
+			if !maps.Equal(state.MarketsToCover, test.markets) {
+				t.Errorf("markets to cover %v, want %v", state.MarketsToCover, test.markets)
+			}
+			if len(state.Trades) != len(test.trades) {
+				t.Errorf("trades %v, want trips for %v", state.Trades, test.trades)
+			}
+			for _, ship_symbol := range test.trades {
+				if _, ok := state.Trades[ship_symbol]; !ok {
+					t.Errorf("the trip %s was on was forgotten", ship_symbol)
+				}
+			}
+		})
+	}
+}
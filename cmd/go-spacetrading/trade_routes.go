@@ -0,0 +1,308 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ianlshaw/go-spacetrading/spacetraders"
+)
+
+// TradeRoute associates a market which exports a TradeGood with one which imports it.
+type TradeRoute struct {
+	BuyMarketplaceWaypointSymbol  string
+	SellMarketplaceWaypointSymbol string
+	BuyWaypoint                   spacetraders.Waypoint
+	BuyMarketTradeGood            spacetraders.TradeGood
+	SellWaypoint                  spacetraders.Waypoint
+	SellMarketTradeGood           spacetraders.TradeGood
+	TradeGoodSymbol               spacetraders.TradeSymbol
+	ProfitPerUnit                 int64
+	Distance                      float64
+	ProfitabilityRating           float64
+}
+
+func MostProfitableTradeRoute(trade_routes []TradeRoute) TradeRoute {
+	most_profitable_trade_route := TradeRoute{}
+	var best_profitability_score = 0.0
+	for _, trade_route := range trade_routes {
+		if trade_route.ProfitabilityRating > best_profitability_score {
+			most_profitable_trade_route = trade_route
+			best_profitability_score = trade_route.ProfitabilityRating
+		}
+	}
+	return most_profitable_trade_route
+}
+
+func TradeRoutesWithTradeGood(trade_routes []TradeRoute, trade_good_symbol spacetraders.TradeSymbol) []TradeRoute {
+	var trade_routes_with_trade_good = []TradeRoute{}
+	for _, trade_route := range trade_routes {
+		if trade_route.TradeGoodSymbol == trade_good_symbol {
+			trade_routes_with_trade_good = append(trade_routes_with_trade_good, trade_route)
+		}
+	}
+	return trade_routes_with_trade_good
+}
+
+func (bot *Bot) UpdateTradeRoutesIncludingThisWaypoint(ctx context.Context, waypoint_symbol string) error {
+	market, err := bot.client.GetMarket(ctx, spacetraders.SystemSymbolOf(waypoint_symbol), waypoint_symbol)
+	if err != nil {
+		return err
+	}
+	bot.record_market(market)
+	return nil
+}
+
+// ApplyMarketToTradeRoutes copies the market's prices into every trade route which buys or sells there.
+func ApplyMarketToTradeRoutes(trade_routes []TradeRoute, market spacetraders.Market) {
+	for i, trade_route := range trade_routes {
+		if market.Symbol == trade_route.BuyWaypoint.Symbol {
+			for _, trade_good := range market.TradeGoods {
+				if trade_route.TradeGoodSymbol == trade_good.Symbol {
+					trade_routes[i].BuyMarketTradeGood = trade_good
+				}
+			}
+		}
+
+		if market.Symbol == trade_route.SellWaypoint.Symbol {
+			for _, trade_good := range market.TradeGoods {
+				if trade_route.TradeGoodSymbol == trade_good.Symbol {
+					trade_routes[i].SellMarketTradeGood = trade_good
+				}
+			}
+		}
+	}
+}
+
+// record_market shares the latest look at a market with every agent, the trade routes are updated
+// through apply_market.
+func (bot *Bot) record_market(market spacetraders.Market) {
+	bot.intel.Record(market)
+}
+
+// follow_intel has the trade routes follow every market any agent looks at from now on.
+func (bot *Bot) follow_intel() {
+	bot.unsubscribe = bot.intel.Subscribe(bot.apply_market)
+}
+
+// apply_market brings the trade routes up to date with a market any agent has looked at.
+func (bot *Bot) apply_market(market spacetraders.Market) {
+	bot.mu.Lock()
+	defer bot.mu.Unlock()
+	ApplyMarketToTradeRoutes(bot.trade_routes, market)
+}
+
+// market returns the last look at the market at waypoint_symbol.
+func (bot *Bot) market(waypoint_symbol string) (spacetraders.Market, bool) {
+	return bot.intel.Market(waypoint_symbol)
+}
+
+// warm_start_trade_routes fills in prices for markets no ship is at yet from the last time one was,
+// so trading can start before the satellites have reported back.
+func (bot *Bot) warm_start_trade_routes() {
+	if bot.intel.history == nil {
+		return
+	}
+	for _, waypoint_symbol := range sorted_market_symbols(bot.markets_to_cover) {
+		if market, _ := bot.market(waypoint_symbol); len(market.TradeGoods) > 0 {
+			continue
+		}
+		snapshot, found := bot.intel.history.LatestPrices(waypoint_symbol)
+		if !found {
+			continue
+		}
+		fmt.Println("[INFO] Using prices from " + snapshot.Timestamp.Format(time.RFC3339) + " for " + waypoint_symbol)
+		bot.intel.Restore(snapshot.Market)
+		ApplyMarketToTradeRoutes(bot.trade_routes, snapshot.Market)
+	}
+}
+
+// trade_routes_snapshot copies the trade routes, so a ship can score them for itself while satellites keep updating prices.
+func (bot *Bot) trade_routes_snapshot() []TradeRoute {
+	bot.mu.Lock()
+	defer bot.mu.Unlock()
+	return append([]TradeRoute(nil), bot.trade_routes...)
+}
+
+func MarketScanComplete(trade_routes []TradeRoute) bool {
+	for _, trade_route := range trade_routes {
+		if trade_route.BuyMarketTradeGood.PurchasePrice == 0 {
+			fmt.Println("[INFO] MARKET DATA INCOMPLETE, WAIT FOR INPUT")
+			return false
+		}
+	}
+	fmt.Println("[INFO] MARKET DATA COMPLETE. LETS TRADE")
+	return true
+}
+
+func (bot *Bot) PopulateTradeRoutesWithWaypointData(ctx context.Context) error {
+	trade_routes := bot.trade_routes
+	fmt.Println("PopulateTradeRoutesWithWaypointData")
+
+	for _, market_waypoint := range sorted_market_symbols(bot.markets_to_cover) {
+		get_waypoint_result, err := bot.client.GetWaypoint(ctx, bot.system_symbol, market_waypoint)
+		if err != nil {
+			return err
+		}
+		for i, trade_route := range trade_routes {
+			if market_waypoint == trade_route.BuyMarketplaceWaypointSymbol {
+				trade_routes[i].BuyWaypoint = get_waypoint_result
+			}
+			if market_waypoint == trade_route.SellMarketplaceWaypointSymbol {
+				trade_routes[i].SellWaypoint = get_waypoint_result
+			}
+		}
+	}
+	return nil
+}
+
+func PopulateTradeRoutesWithDistances(trade_routes []TradeRoute) {
+	for i, trade_route := range trade_routes {
+		distance := spacetraders.DistanceBetweenTwoWaypoints(trade_route.BuyWaypoint, trade_route.SellWaypoint)
+		trade_routes[i].Distance = distance
+	}
+}
+
+func CalculateProfitPerUnit(trade_route TradeRoute) float64 {
+	//fmt.Println("[DEBUG] CalculateProfitPerUnit")
+	sell_price := float64(trade_route.SellMarketTradeGood.SellPrice)
+	buy_price := float64(trade_route.BuyMarketTradeGood.PurchasePrice)
+	profit_per_unit := sell_price - buy_price
+	return profit_per_unit
+}
+
+func PopulateTradeRoutesProfitPerUnit(trade_routes []TradeRoute) {
+	for i, trade_route := range trade_routes {
+		profit_per_unit := CalculateProfitPerUnit(trade_route)
+		trade_routes[i].ProfitPerUnit = int64(profit_per_unit)
+	}
+}
+
+// prices move with every trade volume bought or sold, past a few of them the margin is gone
+const trade_volumes_per_visit = 3
+
+// a unit of FUEL bought at a market puts this much fuel in the tank
+const fuel_per_market_unit = 100
+
+// UnitsPerTrip is how many units of the route's good the ship can expect to move in one trip,
+// limited by its hold and by how far the trade volume at either end lets prices be pushed.
+func UnitsPerTrip(trade_route TradeRoute, ship spacetraders.Ship) int64 {
+	units := ship.Cargo.Capacity
+	for _, trade_volume := range []int64{trade_route.BuyMarketTradeGood.TradeVolume, trade_route.SellMarketTradeGood.TradeVolume} {
+		if trade_volume > 0 && trade_volume*trade_volumes_per_visit < units {
+			units = trade_volume * trade_volumes_per_visit
+		}
+	}
+	return units
+}
+
+// CreditsPerSecond is what running the route once earns the ship, for every second it takes: from where
+// the ship is now to the buy market, then on to the sell market, at CRUISE. The fuel burnt on both legs
+// is paid for at fuel_price, the price of a unit of FUEL on the market.
+func CreditsPerSecond(trade_route TradeRoute, ship spacetraders.Ship, fuel_price int64) float64 {
+	here := ship.Nav.Route.Destination
+	deadhead := spacetraders.DistanceBetweenTwoCoordinates(here.X, here.Y, trade_route.BuyWaypoint.X, trade_route.BuyWaypoint.Y)
+	loaded := spacetraders.DistanceBetweenTwoWaypoints(trade_route.BuyWaypoint, trade_route.SellWaypoint)
+
+	seconds := spacetraders.TravelTime(deadhead, ship.Engine.Speed, spacetraders.FlightModeCruise).Seconds()
+	seconds += spacetraders.TravelTime(loaded, ship.Engine.Speed, spacetraders.FlightModeCruise).Seconds()
+
+	var fuel_cost float64
+	if ship.Fuel.Capacity > 0 {
+		fuel := spacetraders.FuelCost(deadhead, spacetraders.FlightModeCruise) + spacetraders.FuelCost(loaded, spacetraders.FlightModeCruise)
+		fuel_cost = float64(fuel) * float64(fuel_price) / fuel_per_market_unit
+	}
+
+	profit := float64(UnitsPerTrip(trade_route, ship)*trade_route.ProfitPerUnit) - fuel_cost
+	return profit / seconds
+}
+
+// ScoreTradeRoutesForShip rates every route by the credits per second it would earn this ship.
+func (bot *Bot) ScoreTradeRoutesForShip(trade_routes []TradeRoute, ship spacetraders.Ship) {
+	fuel_price := bot.local_fuel_price(ship.Nav.WaypointSymbol)
+	for i, trade_route := range trade_routes {
+		trade_routes[i].ProfitabilityRating = CreditsPerSecond(trade_route, ship, fuel_price)
+	}
+}
+
+// local_fuel_price is what a unit of FUEL costs where the ship is, or failing that the cheapest
+// price a satellite has seen anywhere. It is 0 if nobody has seen one yet.
+func (bot *Bot) local_fuel_price(waypoint_symbol string) int64 {
+	var cheapest int64
+	for symbol, market := range bot.intel.MarketsIn(bot.system_symbol) {
+		for _, trade_good := range market.TradeGoods {
+			if trade_good.Symbol != spacetraders.TradeSymbolFuel || trade_good.PurchasePrice == 0 {
+				continue
+			}
+			if symbol == waypoint_symbol {
+				return trade_good.PurchasePrice
+			}
+			if cheapest == 0 || trade_good.PurchasePrice < cheapest {
+				cheapest = trade_good.PurchasePrice
+			}
+		}
+	}
+	return cheapest
+}
+
+// calculate credits/second generated by running this trade route
+func PrintTradeRoutes(ship_list []spacetraders.Ship, trade_routes []TradeRoute) {
+	for _, trade_route := range trade_routes {
+		fmt.Print("[INFO] BUY ")
+		fmt.Print(trade_route.TradeGoodSymbol)
+		fmt.Print(" AT ")
+		fmt.Print(trade_route.BuyMarketplaceWaypointSymbol)
+		fmt.Print(" FOR ")
+		fmt.Print(trade_route.BuyMarketTradeGood.PurchasePrice)
+		fmt.Print(" SELL AT ")
+		fmt.Print(trade_route.SellMarketplaceWaypointSymbol)
+		fmt.Print(" FOR ")
+		fmt.Print(trade_route.SellMarketTradeGood.SellPrice)
+		fmt.Print(" PPU ")
+		fmt.Print(trade_route.ProfitPerUnit)
+		fmt.Print(" DISTANCE ")
+		fmt.Print(trade_route.Distance)
+		fmt.Print(" CREDITS/S ")
+		fmt.Print(trade_route.ProfitabilityRating)
+		fmt.Println()
+	}
+}
+
+func CountTradeGoodCargo(ship spacetraders.Ship, trade_good_symbol spacetraders.TradeSymbol) int64 {
+	inventory := ship.Cargo.Inventory
+	for _, trade_good := range inventory {
+		if trade_good_symbol == trade_good.Symbol {
+			return trade_good.Units
+		}
+	}
+	return 0
+}
+
+func HowManyTradeGoodCanIAfford(agent spacetraders.Agent, trade_good spacetraders.TradeGood) int64 {
+	fmt.Println("[DEBUG] HowManyTradeGoodCanIAfford")
+	fmt.Print("[DEBUG] agent.Credits = ")
+	fmt.Print(agent.Credits)
+	fmt.Println()
+	fmt.Print("[DEBUG] trade_good.PurchasePrice = ")
+	fmt.Print(trade_good.PurchasePrice)
+	fmt.Println()
+	max_buy_count := agent.Credits / trade_good.PurchasePrice
+	return max_buy_count
+}
+
+// CheapestKnownMarket returns the trade route whose buy market sells trade_good_symbol for the least,
+// considering only markets a satellite has already reported prices for.
+func CheapestKnownMarket(trade_routes []TradeRoute, trade_good_symbol spacetraders.TradeSymbol) (TradeRoute, bool) {
+	cheapest := TradeRoute{}
+	found := false
+	for _, trade_route := range trade_routes {
+		if trade_route.TradeGoodSymbol != trade_good_symbol || trade_route.BuyMarketTradeGood.PurchasePrice == 0 {
+			continue
+		}
+		if !found || trade_route.BuyMarketTradeGood.PurchasePrice < cheapest.BuyMarketTradeGood.PurchasePrice {
+			cheapest = trade_route
+			found = true
+		}
+	}
+	return cheapest, found
+}
@@ -0,0 +1,311 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ianlshaw/go-spacetrading/spacetraders"
+)
+
+// TradeState is where a trading ship is in its trip.
+type TradeState string
+
+const (
+	TradeIdle    TradeState = "IDLE"    // no route chosen
+	TradeToBuy   TradeState = "TO_BUY"  // heading to the route's buy market
+	TradeBuying  TradeState = "BUYING"  // at the buy market, filling the hold
+	TradeToSell  TradeState = "TO_SELL" // heading to the route's sell market
+	TradeSelling TradeState = "SELLING" // at the sell market, emptying the hold
+)
+
+// TradeTrip is what a trading ship is doing, and the route it committed to at the start of the trip,
+// kept until the trip is over however the other routes' prices move in the meantime.
+type TradeTrip struct {
+	State TradeState `json:"state"`
+	Route TradeRoute `json:"route"`
+}
+
+func (bot *Bot) trade_trip(ship_symbol string) TradeTrip {
+	bot.mu.Lock()
+	defer bot.mu.Unlock()
+	trip, ok := bot.trades[ship_symbol]
+	if !ok {
+		return TradeTrip{State: TradeIdle}
+	}
+	return trip
+}
+
+// set_trade_trip moves the ship's trip on and saves the bot's state, so a restart carries on with the same trip.
+func (bot *Bot) set_trade_trip(ship_symbol string, trip TradeTrip) {
+	bot.mu.Lock()
+	if trip.State == TradeIdle {
+		delete(bot.trades, ship_symbol)
+	} else {
+		bot.trades[ship_symbol] = trip
+	}
+	bot.mu.Unlock()
+
+	if trip.State == TradeIdle {
+		fmt.Println("[INFO] " + ship_symbol + " " + string(trip.State))
+	} else {
+		fmt.Println("[INFO] " + ship_symbol + " " + string(trip.State) + " " + TradeRouteLabel(trip.Route))
+	}
+	if err := bot.SaveState(); err != nil {
+		fmt.Println("[ERROR] saving state: " + err.Error())
+	}
+}
+
+// trade takes the ship one step through its trip, carrying straight on to the next state whenever
+// moving to it needs nothing from the game.
+func (bot *Bot) trade(ctx context.Context, ship spacetraders.Ship) error {
+	trip := bot.trade_trip(ship.Symbol)
+	ctx = with_ledger_route(ctx, TradeRouteLabel(trip.Route))
+	units_in_cargo_hold := CountTradeGoodCargo(ship, trip.Route.TradeGoodSymbol)
+
+	switch trip.State {
+	case TradeIdle:
+		trip, chosen, err := bot.choose_trade_route(ctx, ship)
+		if err != nil || !chosen {
+			return err
+		}
+		bot.set_trade_trip(ship.Symbol, trip)
+		return bot.trade(ctx, ship)
+
+	case TradeToBuy:
+		// the hold may have been filled before a crash stopped the trip being saved
+		if units_in_cargo_hold > 0 && ship.Cargo.Units >= ship.Cargo.Capacity {
+			bot.set_trade_trip(ship.Symbol, TradeTrip{State: TradeToSell, Route: trip.Route})
+			return bot.trade(ctx, ship)
+		}
+		if !IsShipAlreadyAtWaypoint(ship, trip.Route.BuyMarketplaceWaypointSymbol) {
+			fmt.Println("[INFO] Heading to " + trip.Route.BuyMarketplaceWaypointSymbol + " to buy " + string(trip.Route.TradeGoodSymbol))
+			return bot.depart(ctx, ship, trip.Route.BuyMarketplaceWaypointSymbol)
+		}
+		bot.set_trade_trip(ship.Symbol, TradeTrip{State: TradeBuying, Route: trip.Route})
+		return bot.trade(ctx, ship)
+
+	case TradeBuying:
+		bought, err := bot.buy_trade_goods(ctx, ship, trip.Route)
+		if err != nil {
+			return err
+		}
+		if units_in_cargo_hold+bought == 0 {
+			fmt.Println("[INFO] Could not afford any " + trip.Route.TradeGoodSymbol + ", giving up on the trip")
+			bot.set_trade_trip(ship.Symbol, TradeTrip{State: TradeIdle})
+			return nil
+		}
+		bot.set_trade_trip(ship.Symbol, TradeTrip{State: TradeToSell, Route: trip.Route})
+		return nil
+
+	case TradeToSell:
+		if units_in_cargo_hold == 0 {
+			bot.set_trade_trip(ship.Symbol, TradeTrip{State: TradeIdle})
+			return nil
+		}
+		if !IsShipAlreadyAtWaypoint(ship, trip.Route.SellMarketplaceWaypointSymbol) {
+			fmt.Println("[INFO] Taking " + string(trip.Route.TradeGoodSymbol) + " to " + trip.Route.SellMarketplaceWaypointSymbol)
+			return bot.depart(ctx, ship, trip.Route.SellMarketplaceWaypointSymbol)
+		}
+		bot.set_trade_trip(ship.Symbol, TradeTrip{State: TradeSelling, Route: trip.Route})
+		return bot.trade(ctx, ship)
+
+	case TradeSelling:
+		if err := bot.sell_trade_goods(ctx, ship, trip.Route, units_in_cargo_hold); err != nil {
+			return err
+		}
+		fmt.Println("[INFO] Trip complete " + TradeRouteLabel(trip.Route))
+		bot.set_trade_trip(ship.Symbol, TradeTrip{State: TradeIdle})
+		return nil
+	}
+
+	fmt.Println("[ERROR] " + ship.Symbol + " is in unknown trade state " + string(trip.State) + ", starting over")
+	bot.set_trade_trip(ship.Symbol, TradeTrip{State: TradeIdle})
+	return nil
+}
+
+// choose_trade_route picks the route earning this ship the most credits per second. Cargo left over
+// from before, such as from an older version of the bot, is sold off first, down whichever trade route
+// it fetches most on. Nothing is chosen while cargo no route carries is still being got rid of.
+func (bot *Bot) choose_trade_route(ctx context.Context, ship spacetraders.Ship) (TradeTrip, bool, error) {
+	trade_routes := bot.trade_routes_snapshot()
+	if !MarketScanComplete(trade_routes) {
+		return TradeTrip{}, false, nil
+	}
+	PopulateTradeRoutesProfitPerUnit(trade_routes)
+	bot.ScoreTradeRoutesForShip(trade_routes, ship)
+	PrintTradeRoutes(nil, trade_routes)
+
+	if !is_ship_cargo_empty(ship) {
+		cleared, err := bot.clear_stranded_cargo(ctx, ship, trade_routes)
+		if err != nil || !cleared {
+			return TradeTrip{}, false, err
+		}
+		// every route carrying it might be losing money now, it is still better sold than kept
+		var best TradeRoute
+		var best_worth int64
+		found := false
+		for _, item := range ship.Cargo.Inventory {
+			for _, trade_route := range TradeRoutesWithTradeGood(trade_routes, item.Symbol) {
+				worth := trade_route.SellMarketTradeGood.SellPrice * item.Units
+				if !found || worth > best_worth {
+					best, best_worth, found = trade_route, worth, true
+				}
+			}
+		}
+		if found {
+			return TradeTrip{State: TradeToSell, Route: best}, true, nil
+		}
+	}
+
+	most_profitable_trade_route := MostProfitableTradeRoute(trade_routes)
+	if most_profitable_trade_route.ProfitabilityRating <= 0 {
+		fmt.Println("[INFO] No trade route makes money right now")
+		return TradeTrip{}, false, nil
+	}
+	return TradeTrip{State: TradeToBuy, Route: most_profitable_trade_route}, true, nil
+}
+
+// clear_stranded_cargo gets rid of cargo no trade route carries, which would otherwise sit in the hold
+// for good. It is sold the way miners sell their ore, or jettisoned if no known market buys it at all.
+// It reports whether the hold is clear of it yet.
+func (bot *Bot) clear_stranded_cargo(ctx context.Context, ship spacetraders.Ship, trade_routes []TradeRoute) (bool, error) {
+	ctx = with_ledger_route(ctx, "LEFTOVERS")
+	stranded := ship
+	stranded.Cargo.Inventory = nil
+	for _, item := range ship.Cargo.Inventory {
+		if len(TradeRoutesWithTradeGood(trade_routes, item.Symbol)) > 0 {
+			continue
+		}
+		if _, _, found := bot.best_known_buyer(item.Symbol); !found {
+			fmt.Println("[INFO] Jettisoning " + item.Symbol + ", no market buys it")
+			if _, err := bot.client.Jettison(ctx, ship.Symbol, item.Symbol, item.Units); err != nil {
+				return false, err
+			}
+			continue
+		}
+		stranded.Cargo.Inventory = append(stranded.Cargo.Inventory, item)
+	}
+	if len(stranded.Cargo.Inventory) == 0 {
+		return true, nil
+	}
+	fmt.Println("[INFO] No trade route carries what is left in the hold, selling it off")
+	return false, bot.sell_cargo(ctx, stranded)
+}
+
+// buy_trade_goods fills the hold with the route's good, as far as the credits go, and fills the tank.
+func (bot *Bot) buy_trade_goods(ctx context.Context, ship spacetraders.Ship, trade_route TradeRoute) (bought int64, err error) {
+	if !IsShipDocked(ship) {
+		if _, err := bot.client.DockShip(ctx, ship.Symbol); err != nil {
+			return 0, ignore_in_transit(ship.Symbol, err)
+		}
+	}
+	if ship.Fuel.Capacity > 0 && bot.sells_fuel(ship.Nav.WaypointSymbol) {
+		if _, err := bot.client.RefuelShip(ctx, ship.Symbol); err != nil {
+			return 0, err
+		}
+	}
+
+	// the price may have moved since the satellite looked
+	market, err := bot.client.GetMarket(ctx, spacetraders.SystemSymbolOf(trade_route.BuyMarketplaceWaypointSymbol), trade_route.BuyMarketplaceWaypointSymbol)
+	if err != nil {
+		return 0, err
+	}
+	bot.record_market(market)
+	trade_good := trade_route.BuyMarketTradeGood
+	for _, each_trade_good := range market.TradeGoods {
+		if each_trade_good.Symbol == trade_route.TradeGoodSymbol {
+			trade_good = each_trade_good
+		}
+	}
+
+	agent, err := bot.client.GetAgent(ctx)
+	if err != nil {
+		return 0, err
+	}
+	units_to_purchase := ship.Cargo.Capacity - ship.Cargo.Units
+	if trade_good.PurchasePrice > 0 {
+		if maximum_affordable_units := HowManyTradeGoodCanIAfford(agent, trade_good); maximum_affordable_units < units_to_purchase {
+			units_to_purchase = maximum_affordable_units
+		}
+	}
+
+	bought, err = bot.buy_units(ctx, ship.Symbol, trade_route.TradeGoodSymbol, units_to_purchase, trade_good.TradeVolume)
+	if err != nil {
+		return bought, err
+	}
+	fmt.Print("[INFO] Bought " + trade_route.TradeGoodSymbol + " ")
+	fmt.Println(bought)
+	return bought, nil
+}
+
+// sell_trade_goods empties the hold of the route's good, and fills the tank for the next trip.
+func (bot *Bot) sell_trade_goods(ctx context.Context, ship spacetraders.Ship, trade_route TradeRoute, units_to_sell int64) error {
+	if !IsShipDocked(ship) {
+		if _, err := bot.client.DockShip(ctx, ship.Symbol); err != nil {
+			return ignore_in_transit(ship.Symbol, err)
+		}
+	}
+
+	if err := bot.sell_units(ctx, ship.Symbol, trade_route.TradeGoodSymbol, units_to_sell, trade_route.SellMarketTradeGood.TradeVolume); err != nil {
+		return err
+	}
+
+	if ship.Fuel.Capacity > 0 && bot.sells_fuel(ship.Nav.WaypointSymbol) {
+		if _, err := bot.client.RefuelShip(ctx, ship.Symbol); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buy_units buys units of trade_symbol, at most trade_volume at a time or all at once if that is 0, and
+// returns how many it bought. Prices rise as we buy, so running out of credits part way is not an error,
+// the ship leaves with what it could afford.
+func (bot *Bot) buy_units(ctx context.Context, ship_symbol string, trade_symbol spacetraders.TradeSymbol, units_to_buy int64, trade_volume int64) (bought int64, err error) {
+	for units_to_buy > 0 {
+		units := units_to_buy
+		if trade_volume > 0 && units > trade_volume {
+			units = trade_volume
+		}
+		if _, err := bot.client.PurchaseCargo(ctx, ship_symbol, trade_symbol, units); err != nil {
+			if spacetraders.IsAPIErrorCode(err, spacetraders.ErrorCodeInsufficientCredits) {
+				fmt.Println("[INFO] Ran out of credits, leaving with what we have")
+				return bought, nil
+			}
+			// the market's trade volume has shrunk since we last saw it
+			if spacetraders.IsAPIErrorCode(err, spacetraders.ErrorCodeMarketTradeUnitLimit) && units > 1 {
+				trade_volume = units / 2
+				continue
+			}
+			return bought, err
+		}
+		bought += units
+		units_to_buy -= units
+	}
+	return bought, nil
+}
+
+// sell_units sells units of trade_symbol, at most trade_volume at a time or all at once if that is 0.
+func (bot *Bot) sell_units(ctx context.Context, ship_symbol string, trade_symbol spacetraders.TradeSymbol, units_to_sell int64, trade_volume int64) error {
+	for units_to_sell > 0 {
+		units := units_to_sell
+		if trade_volume > 0 && units > trade_volume {
+			units = trade_volume
+		}
+		sold, err := bot.client.SellCargo(ctx, ship_symbol, trade_symbol, units)
+		if err != nil {
+			// the market's trade volume has shrunk since we last saw it
+			if spacetraders.IsAPIErrorCode(err, spacetraders.ErrorCodeMarketTradeUnitLimit) && units > 1 {
+				trade_volume = units / 2
+				continue
+			}
+			return err
+		}
+		fmt.Print("[INFO] Sold " + trade_symbol + " ")
+		fmt.Print(units)
+		fmt.Print(" for ")
+		fmt.Println(sold.Transaction.TotalPrice)
+		units_to_sell -= units
+	}
+	return nil
+}
@@ -1,3 +1,3 @@
-module example/hello
+module github.com/ianlshaw/go-spacetrading
 
 go 1.22.5
@@ -0,0 +1,250 @@
+package spacetraders
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// GetStatus returns the server status, including when the universe was last reset and when it will be next.
+func (client *Client) GetStatus(ctx context.Context) (ServerStatus, error) {
+	status := ServerStatus{}
+	err := client.decode_get(ctx, "", &status)
+	return status, err
+}
+
+// DefaultFaction is the faction agents join unless they ask for another.
+const DefaultFaction = "COSMIC"
+
+// ValidateCallsign checks callsign against the game's rules for agent symbols before it is sent:
+// 3 to 14 letters, digits, hyphens or underscores.
+func ValidateCallsign(callsign string) error {
+	if len(callsign) < 3 || len(callsign) > 14 {
+		return fmt.Errorf("callsign %q must be 3 to 14 characters long", callsign)
+	}
+	for _, character := range callsign {
+		switch {
+		case character >= 'A' && character <= 'Z', character >= 'a' && character <= 'z', character >= '0' && character <= '9', character == '-', character == '_':
+		default:
+			return fmt.Errorf("callsign %q may only contain letters, digits, hyphens and underscores", callsign)
+		}
+	}
+	return nil
+}
+
+// RegisterAgent claims payload.Symbol for a new agent in payload.Faction, DefaultFaction if that is empty,
+// and returns the agent, its starting ship and contract, and its token. The game wants registrations
+// made with the account token, see Client.AccountToken. The agent's token is not stored on the client,
+// callers decide where it lives.
+func (client *Client) RegisterAgent(ctx context.Context, payload RegisterAgentPayload) (RegisterAgentResponse, error) {
+	if err := ValidateCallsign(payload.Symbol); err != nil {
+		return RegisterAgentResponse{}, err
+	}
+	if payload.Faction == "" {
+		payload.Faction = DefaultFaction
+	}
+	payload.Faction = strings.ToUpper(payload.Faction)
+
+	data_container := RegisterAgentResponseData{}
+	if err := client.decode_post(ctx, "register", payload, &data_container); err != nil {
+		var api_error *APIError
+		if errors.As(err, &api_error) && api_error.Data != nil {
+			// why the registration was turned down, such as which field failed validation, is in the data
+			reason, _ := json.Marshal(api_error.Data)
+			return RegisterAgentResponse{}, fmt.Errorf("registering %s: %w: %s", payload.Symbol, err, reason)
+		}
+		return RegisterAgentResponse{}, fmt.Errorf("registering %s: %w", payload.Symbol, err)
+	}
+	if data_container.Data.Token == "" {
+		return RegisterAgentResponse{}, fmt.Errorf("registering %s: the game did not send back a token", payload.Symbol)
+	}
+	return data_container.Data, nil
+}
+
+func (client *Client) GetAgent(ctx context.Context) (Agent, error) {
+	endpoint := "my/agent"
+	data_container := GetAgentResponseData{}
+	err := client.decode_get(ctx, endpoint, &data_container)
+	return data_container.Data, err
+}
+
+func (client *Client) ListShips(ctx context.Context) ([]Ship, error) {
+	return client.ListShipsPager(ctx).All()
+}
+
+func (client *Client) GetShip(ctx context.Context, ship_symbol string) (Ship, error) {
+	endpoint := "my/ships/" + ship_symbol
+	data_container := GetShipResponseData{}
+	err := client.decode_get(ctx, endpoint, &data_container)
+	return data_container.Data, err
+}
+
+// ListShipsPager iterates over every ship in the fleet, page by page.
+func (client *Client) ListShipsPager(ctx context.Context) *Pager[Ship] {
+	endpoint := "my/ships"
+	return NewPager(func(page int64) ([]Ship, Meta, error) {
+		data_container := ListShipsResponseData{}
+		err := client.decode_get(ctx, paged_endpoint(endpoint, page), &data_container)
+		return data_container.Data, data_container.Meta, err
+	})
+}
+
+func (client *Client) GetWaypoint(ctx context.Context, system_symbol string, waypoint_symbol string) (Waypoint, error) {
+	endpoint := "systems/" + system_symbol + "/waypoints/" + waypoint_symbol
+	data_container := GetWaypointResponseData{}
+	err := client.decode_get(ctx, endpoint, &data_container)
+	return data_container.Data, err
+}
+
+// ListWaypointsInSystem iterates over every waypoint in the system, page by page.
+func (client *Client) ListWaypointsInSystem(ctx context.Context, system_symbol string) *Pager[Waypoint] {
+	return client.list_waypoints_in_system_pager(ctx, "systems/"+system_symbol+"/waypoints")
+}
+
+func (client *Client) list_waypoints_in_system_pager(ctx context.Context, endpoint string) *Pager[Waypoint] {
+	return NewPager(func(page int64) ([]Waypoint, Meta, error) {
+		data_container := ListWaypointsInSystemResponseData{}
+		err := client.decode_get(ctx, paged_endpoint(endpoint, page), &data_container)
+		return data_container.Data, data_container.Meta, err
+	})
+}
+
+func (client *Client) ListWaypointsInSystemByTrait(ctx context.Context, system_symbol string, trait WaypointTrait) ([]Waypoint, error) {
+	endpoint := "systems/" + system_symbol + "/waypoints?traits=" + string(trait)
+	return client.list_waypoints_in_system_pager(ctx, endpoint).All()
+}
+
+func (client *Client) ListWaypointsInSystemByType(ctx context.Context, system_symbol string, query_type WaypointType) ([]Waypoint, error) {
+	endpoint := "systems/" + system_symbol + "/waypoints?type=" + string(query_type)
+	return client.list_waypoints_in_system_pager(ctx, endpoint).All()
+}
+
+func (client *Client) GetMarket(ctx context.Context, system_symbol string, waypoint_symbol string) (Market, error) {
+	endpoint := "systems/" + system_symbol + "/waypoints/" + waypoint_symbol + "/market"
+	data_container := GetMarketResponseData{}
+	err := client.decode_get(ctx, endpoint, &data_container)
+	return data_container.Data, err
+}
+
+func (client *Client) GetShipyard(ctx context.Context, system_symbol string, waypoint_symbol string) (Shipyard, error) {
+	endpoint := "systems/" + system_symbol + "/waypoints/" + waypoint_symbol + "/shipyard"
+	data_container := GetShipyardResponseData{}
+	err := client.decode_get(ctx, endpoint, &data_container)
+	return data_container.Data, err
+}
+
+// GetJumpGate returns the jump gates this one connects to. The waypoint must be a charted JUMP_GATE.
+func (client *Client) GetJumpGate(ctx context.Context, system_symbol string, waypoint_symbol string) (GetJumpGateResponse, error) {
+	endpoint := "systems/" + system_symbol + "/waypoints/" + waypoint_symbol + "/jump-gate"
+	data_container := GetJumpGateResponseData{}
+	err := client.decode_get(ctx, endpoint, &data_container)
+	return data_container.Data, err
+}
+
+func (client *Client) NavigateShip(ctx context.Context, ship_symbol string, waypoint_symbol string) (NavigateShipResponse, error) {
+	endpoint := "my/ships/" + ship_symbol + "/navigate"
+	payload := &NavigateShipPayload{}
+	payload.WaypointSymbol = waypoint_symbol
+	data_container := NavigateShipResponseData{}
+	err := client.decode_post(ctx, endpoint, payload, &data_container)
+	return data_container.Data, err
+}
+
+// PatchShipNav sets the flight mode the ship uses from its next trip on, one of CRUISE, BURN, DRIFT or STEALTH.
+func (client *Client) PatchShipNav(ctx context.Context, ship_symbol string, flight_mode FlightMode) (Nav, error) {
+	endpoint := "my/ships/" + ship_symbol + "/nav"
+	payload := &PatchShipNavPayload{}
+	payload.FlightMode = flight_mode
+	data_container := PatchShipNavResponseData{}
+	err := client.decode_patch(ctx, endpoint, payload, &data_container)
+	return data_container.Data, err
+}
+
+// JumpShip sends a ship orbiting a jump gate to a connected gate in another system. It arrives
+// straight away but goes on cooldown.
+func (client *Client) JumpShip(ctx context.Context, ship_symbol string, waypoint_symbol string) (JumpShipResponse, error) {
+	endpoint := "my/ships/" + ship_symbol + "/jump"
+	payload := &JumpShipPayload{}
+	payload.WaypointSymbol = waypoint_symbol
+	data_container := JumpShipResponseData{}
+	err := client.decode_post(ctx, endpoint, payload, &data_container)
+	if err == nil {
+		client.report_transaction(ctx, data_container.Data.Transaction, -data_container.Data.Transaction.TotalPrice, data_container.Data.Agent)
+	}
+	return data_container.Data, err
+}
+
+func (client *Client) OrbitShip(ctx context.Context, ship_symbol string) (OrbitShipResponse, error) {
+	endpoint := "my/ships/" + ship_symbol + "/orbit"
+	payload := &EmptyPayload{}
+	data_container := OrbitShipResponseData{}
+	err := client.decode_post(ctx, endpoint, payload, &data_container)
+	return data_container.Data, err
+}
+
+func (client *Client) DockShip(ctx context.Context, ship_symbol string) (DockShipResponse, error) {
+	endpoint := "my/ships/" + ship_symbol + "/dock"
+	payload := &EmptyPayload{}
+	data_container := DockShipResponseData{}
+	err := client.decode_post(ctx, endpoint, payload, &data_container)
+	return data_container.Data, err
+}
+
+func (client *Client) PurchaseShip(ctx context.Context, ship_type ShipType, waypoint_symbol string) (PurchaseShipResponse, error) {
+	endpoint := "my/ships/"
+	payload := &PurchaseShipPayload{}
+	payload.WaypointSymbol = waypoint_symbol
+	payload.ShipType = ship_type
+	data_container := PurchaseShipResponseData{}
+	err := client.decode_post(ctx, endpoint, payload, &data_container)
+	if err == nil {
+		// shipyards give the price on its own rather than as a total
+		price := data_container.Data.Transaction.Price
+		if price == 0 {
+			price = data_container.Data.Transaction.TotalPrice
+		}
+		client.report_transaction(ctx, data_container.Data.Transaction, -price, data_container.Data.Agent)
+	}
+	return data_container.Data, err
+}
+
+func (client *Client) PurchaseCargo(ctx context.Context, ship_symbol string, trade_good_symbol TradeSymbol, units int64) (PurchaseCargoResponse, error) {
+	endpoint := "my/ships/" + ship_symbol + "/purchase"
+	payload := &PurchaseCargoPayload{}
+	payload.Symbol = trade_good_symbol
+	payload.Units = units
+	data_container := PurchaseCargoResponseData{}
+	err := client.decode_post(ctx, endpoint, payload, &data_container)
+	if err == nil {
+		client.report_transaction(ctx, data_container.Data.Transaction, -data_container.Data.Transaction.TotalPrice, data_container.Data.Agent)
+	}
+	return data_container.Data, err
+}
+
+func (client *Client) SellCargo(ctx context.Context, ship_symbol string, trade_good_symbol TradeSymbol, units int64) (SellCargoResponse, error) {
+	endpoint := "my/ships/" + ship_symbol + "/sell"
+	payload := &SellCargoPayload{}
+	payload.Symbol = trade_good_symbol
+	payload.Units = units
+	data_container := SellCargoResponseData{}
+	err := client.decode_post(ctx, endpoint, payload, &data_container)
+	if err == nil {
+		client.report_transaction(ctx, data_container.Data.Transaction, data_container.Data.Transaction.TotalPrice, data_container.Data.Agent)
+	}
+	return data_container.Data, err
+}
+
+func (client *Client) RefuelShip(ctx context.Context, ship_symbol string) (RefuelShipResponse, error) {
+	endpoint := "my/ships/" + ship_symbol + "/refuel"
+	payload := &RefuelShipPayload{}
+	//payload.Units = units
+	//payload.FromCargo = false
+	data_container := RefuelShipResponseData{}
+	err := client.decode_post(ctx, endpoint, payload, &data_container)
+	if err == nil {
+		client.report_transaction(ctx, data_container.Data.Transaction, -data_container.Data.Transaction.TotalPrice, data_container.Data.Agent)
+	}
+	return data_container.Data, err
+}
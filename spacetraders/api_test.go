@@ -0,0 +1,44 @@
+package spacetraders
+
+import (
+	"context"
+	"testing"
+)
+
+func TestValidateCallsign(t *testing.T) {
+	tests := []struct {
+		callsign string
+		valid    bool
+	}{
+		{"TESTER", true},
+		{"abc", true},
+		{"ABCDEFGHIJKLMN", true},
+		{"SPACE_TRADE-01", true},
+		{"AB", false},
+		{"ABCDEFGHIJKLMNO", false},
+		{"", false},
+		{"TEST ER", false},
+		{"TEST.ER", false},
+		{"TÉSTER", false},
+	}
+	for _, test := range tests {
+		err := ValidateCallsign(test.callsign)
+		if test.valid && err != nil {
+			t.Errorf("ValidateCallsign(%q) = %v, want it accepted", test.callsign, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("ValidateCallsign(%q) accepted it, want an error", test.callsign)
+		}
+	}
+}
+
+func TestRegisterAgentValidatesFirst(t *testing.T) {
+	// nothing listens here, so only a request that is never sent comes back with the validation error
+	client := NewClient("")
+	client.BaseURL = "http://127.0.0.1:1/v2/"
+	client.Limiter = nil
+	_, err := client.RegisterAgent(context.Background(), RegisterAgentPayload{Symbol: "AB"})
+	if err == nil || err.Error() != ValidateCallsign("AB").Error() {
+		t.Errorf("got %v registering AB, want the callsign turned down before it is sent", err)
+	}
+}
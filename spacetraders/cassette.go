@@ -0,0 +1,252 @@
+package spacetraders
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+)
+
+// ErrCassetteMismatch is returned when a replayed session asks for something the cassette does not have next.
+var ErrCassetteMismatch = errors.New("cassette mismatch")
+
+// ReplayToken stands in for the agent's token in a cassette. The token the game hands out on
+// registering is written as this, so a client replaying a session should use it too.
+const ReplayToken = "replay"
+
+// Interaction is one request and its response as stored in a cassette.
+// The Authorization header is never recorded, and neither is the token handed out on registering,
+// see ReplayToken.
+type Interaction struct {
+	Method       string      `json:"method"`
+	Path         string      `json:"path"`
+	RequestBody  string      `json:"requestBody,omitempty"`
+	StatusCode   int         `json:"statusCode"`
+	Header       http.Header `json:"header"`
+	ResponseBody string      `json:"responseBody"`
+
+	// why the request got no response at all, empty if it did
+	Error string `json:"error,omitempty"`
+}
+
+// Recorder is an http.RoundTripper which passes requests on to Transport and appends
+// every interaction to a cassette file, one JSON object per line.
+type Recorder struct {
+	Transport http.RoundTripper
+
+	// the client's BaseURL, paths are recorded relative to it
+	BaseURL string
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewRecorder appends to the cassette at filename, creating it if needed. base_url is the BaseURL of
+// the client it records for.
+func NewRecorder(filename string, base_url string, transport http.RoundTripper) (*Recorder, error) {
+	f, err := os.OpenFile(filename, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &Recorder{Transport: transport, BaseURL: base_url, file: f}, nil
+}
+
+func (recorder *Recorder) RoundTrip(request *http.Request) (*http.Response, error) {
+	request_body, err := read_request_body(request)
+	if err != nil {
+		return nil, err
+	}
+	interaction := Interaction{
+		Method:      request.Method,
+		Path:        cassette_path(request, recorder.BaseURL),
+		RequestBody: string(request_body),
+	}
+
+	// a failed round trip is on the tape too, or the retry which followed it puts the replay out of step
+	response, err := recorder.Transport.RoundTrip(request)
+	if err != nil {
+		interaction.Error = err.Error()
+		return nil, errors.Join(err, recorder.write(interaction))
+	}
+	response_body, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		interaction.Error = err.Error()
+		return nil, errors.Join(err, recorder.write(interaction))
+	}
+	response.Body = io.NopCloser(bytes.NewReader(response_body))
+
+	interaction.StatusCode = response.StatusCode
+	interaction.Header = response.Header
+	interaction.ResponseBody = string(response_body)
+	if strings.HasSuffix(interaction.Path, "/register") {
+		interaction.ResponseBody = without_token(interaction.ResponseBody)
+	}
+	if err := recorder.write(interaction); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (recorder *Recorder) write(interaction Interaction) error {
+	line, err := json.Marshal(interaction)
+	if err != nil {
+		return err
+	}
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if _, err := recorder.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("cassette: %w", err)
+	}
+	return nil
+}
+
+// without_token swaps the agent token in a registration response for ReplayToken.
+func without_token(response_body string) string {
+	registration := RegisterAgentResponseData{}
+	if err := json.Unmarshal([]byte(response_body), &registration); err != nil || registration.Data.Token == "" {
+		return response_body
+	}
+	return strings.ReplaceAll(response_body, registration.Data.Token, ReplayToken)
+}
+
+func (recorder *Recorder) Close() error {
+	return recorder.file.Close()
+}
+
+// Replayer is an http.RoundTripper which answers requests from a cassette, in the order they
+// were recorded, without touching the network. A request which does not match the next
+// interaction on the tape is an error, so a replayed session that diverges stops immediately.
+// A request which failed without a response when it was recorded fails again the same way.
+type Replayer struct {
+	// the client's BaseURL, requests are matched on their path relative to it
+	BaseURL string
+
+	mu           sync.Mutex
+	interactions []Interaction
+	position     int
+	done         chan struct{}
+}
+
+// NewReplayer plays back the cassette at filename. base_url is the BaseURL of the client it answers.
+func NewReplayer(filename string, base_url string) (*Replayer, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	replayer := &Replayer{BaseURL: base_url, done: make(chan struct{})}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		interaction := Interaction{}
+		if err := json.Unmarshal(scanner.Bytes(), &interaction); err != nil {
+			return nil, fmt.Errorf("cassette %s line %d: %w", filename, len(replayer.interactions)+1, err)
+		}
+		replayer.interactions = append(replayer.interactions, interaction)
+	}
+	if len(replayer.interactions) == 0 {
+		close(replayer.done)
+	}
+	return replayer, scanner.Err()
+}
+
+// Done is closed once the last interaction has been played back.
+func (replayer *Replayer) Done() <-chan struct{} {
+	return replayer.done
+}
+
+// Remaining returns how many interactions have not been played back yet.
+func (replayer *Replayer) Remaining() int {
+	replayer.mu.Lock()
+	defer replayer.mu.Unlock()
+	return len(replayer.interactions) - replayer.position
+}
+
+// Peek returns the next interaction to be played back, if any.
+func (replayer *Replayer) Peek() (Interaction, bool) {
+	replayer.mu.Lock()
+	defer replayer.mu.Unlock()
+	if replayer.position >= len(replayer.interactions) {
+		return Interaction{}, false
+	}
+	return replayer.interactions[replayer.position], true
+}
+
+func (replayer *Replayer) RoundTrip(request *http.Request) (*http.Response, error) {
+	request_body, err := read_request_body(request)
+	if err != nil {
+		return nil, err
+	}
+
+	replayer.mu.Lock()
+	defer replayer.mu.Unlock()
+
+	path := cassette_path(request, replayer.BaseURL)
+	if replayer.position >= len(replayer.interactions) {
+		return nil, fmt.Errorf("%w: no interaction left for %s %s", ErrCassetteMismatch, request.Method, path)
+	}
+	interaction := replayer.interactions[replayer.position]
+	if interaction.Method != request.Method || interaction.Path != path || interaction.RequestBody != string(request_body) {
+		return nil, fmt.Errorf("%w: interaction %d is %s %s, got %s %s", ErrCassetteMismatch, replayer.position+1, interaction.Method, interaction.Path, request.Method, path)
+	}
+	replayer.position++
+	if replayer.position == len(replayer.interactions) {
+		close(replayer.done)
+	}
+	if interaction.Error != "" {
+		return nil, errors.New(interaction.Error)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.StatusCode, http.StatusText(interaction.StatusCode)),
+		StatusCode:    interaction.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        interaction.Header.Clone(),
+		Body:          io.NopCloser(strings.NewReader(interaction.ResponseBody)),
+		ContentLength: int64(len(interaction.ResponseBody)),
+		Request:       request,
+	}, nil
+}
+
+// cassette_path identifies a request by where it is under base_url, leaving out the host and the
+// base url's own path, so a cassette recorded against the real game can be replayed with any base url.
+func cassette_path(request *http.Request, base_url string) string {
+	path := request.URL.RequestURI()
+	base, err := url.Parse(base_url)
+	if err != nil {
+		return path
+	}
+	return "/" + strings.TrimPrefix(path, strings.TrimSuffix(base.Path, "/")+"/")
+}
+
+// read_request_body reads the body and puts it back so the request can still be sent.
+func read_request_body(request *http.Request) ([]byte, error) {
+	if request.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(request.Body)
+	request.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	request.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
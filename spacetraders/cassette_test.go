@@ -0,0 +1,194 @@
+package spacetraders_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ianlshaw/go-spacetrading/spacetraders"
+	"github.com/ianlshaw/go-spacetrading/spacetraders/fake"
+)
+
+// session is what a first run does: sign up, then look around.
+type session struct {
+	token string
+	agent spacetraders.Agent
+	ships []spacetraders.Ship
+}
+
+func play(t *testing.T, client *spacetraders.Client) session {
+	t.Helper()
+	ctx := context.Background()
+	registration, err := client.RegisterAgent(ctx, spacetraders.RegisterAgentPayload{Symbol: "TESTER"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.Token = registration.Token
+	agent, err := client.GetAgent(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ships, err := client.ListShips(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return session{token: registration.Token, agent: agent, ships: ships}
+}
+
+func replayer_client(t *testing.T, cassette string) (*spacetraders.Client, *spacetraders.Replayer) {
+	t.Helper()
+	// nothing listens here, so any request the cassette does not answer fails
+	client := spacetraders.NewClient(spacetraders.ReplayToken)
+	client.BaseURL = "http://127.0.0.1:1/v2/"
+	client.Limiter = nil
+	replayer, err := spacetraders.NewReplayer(cassette, client.BaseURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.HTTPClient.Transport = replayer
+	return client, replayer
+}
+
+func TestRecordThenReplay(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+	cassette := filepath.Join(t.TempDir(), "session.jsonl")
+
+	client := server.Client("")
+	recorder, err := spacetraders.NewRecorder(cassette, client.BaseURL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.HTTPClient.Transport = recorder
+	recorded := play(t, client)
+	recorder.Close()
+
+	tape, err := os.ReadFile(cassette)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(tape), recorded.token) {
+		t.Error("the agent's token was written to the cassette")
+	}
+	if strings.Contains(string(tape), "/v2/") {
+		t.Error("the cassette has paths with the base url's path in")
+	}
+
+	replay, replayer := replayer_client(t, cassette)
+	replayed := play(t, replay)
+	if replayed.token != spacetraders.ReplayToken {
+		t.Errorf("replayed registration handed out %q, want %q", replayed.token, spacetraders.ReplayToken)
+	}
+	if !reflect.DeepEqual(replayed.agent, recorded.agent) {
+		t.Errorf("replayed agent %+v, recorded %+v", replayed.agent, recorded.agent)
+	}
+	if !reflect.DeepEqual(replayed.ships, recorded.ships) {
+		t.Errorf("replayed ships %+v, recorded %+v", replayed.ships, recorded.ships)
+	}
+	select {
+	case <-replayer.Done():
+	default:
+		t.Errorf("%d interactions left on the tape", replayer.Remaining())
+	}
+
+	if _, err := replay.GetAgent(context.Background()); !errors.Is(err, spacetraders.ErrCassetteMismatch) {
+		t.Errorf("got %v once the tape ran out, want a cassette mismatch", err)
+	}
+}
+
+func TestReplayDiverges(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+	cassette := filepath.Join(t.TempDir(), "session.jsonl")
+
+	client := server.Client("")
+	recorder, err := spacetraders.NewRecorder(cassette, client.BaseURL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.HTTPClient.Transport = recorder
+	play(t, client)
+	recorder.Close()
+
+	replay, _ := replayer_client(t, cassette)
+	_, err = replay.GetAgent(context.Background())
+	if !errors.Is(err, spacetraders.ErrCassetteMismatch) {
+		t.Errorf("got %v asking for the agent before registering, want a cassette mismatch", err)
+	}
+}
+
+// flaky fails the first request it is given for each of paths, then passes everything on.
+type flaky struct {
+	paths  []string
+	failed map[string]bool
+}
+
+func (transport *flaky) RoundTrip(request *http.Request) (*http.Response, error) {
+	for _, path := range transport.paths {
+		if !transport.failed[path] && strings.HasSuffix(request.URL.Path, path) {
+			transport.failed[path] = true
+			return nil, errors.New("connection reset by peer")
+		}
+	}
+	return http.DefaultTransport.RoundTrip(request)
+}
+
+// fly sets a ship off somewhere, returning why the game did not hear if it did not, and then looks at it.
+func fly(t *testing.T, client *spacetraders.Client, ship_symbol string) (navigate_err error, ship spacetraders.Ship) {
+	t.Helper()
+	ctx := context.Background()
+	if _, err := client.OrbitShip(ctx, ship_symbol); err != nil {
+		t.Fatal(err)
+	}
+	_, err := client.NavigateShip(ctx, ship_symbol, "X1-FAKE-B2")
+	ship, get_err := client.GetShip(ctx, ship_symbol)
+	if get_err != nil {
+		t.Fatal(get_err)
+	}
+	return err, ship
+}
+
+func TestReplayRecordedFailure(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+	cassette := filepath.Join(t.TempDir(), "session.jsonl")
+
+	// a GET which is retried, and a navigation which is not safe to send again and so fails
+	transport := &flaky{paths: []string{"/my/agent", "/navigate"}, failed: make(map[string]bool)}
+	client := server.Client("")
+	client.RetryBaseDelay, client.RetryMaxDelay = time.Millisecond, time.Millisecond
+	recorder, err := spacetraders.NewRecorder(cassette, client.BaseURL, transport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.HTTPClient.Transport = recorder
+	recorded := play(t, client)
+	recorded_err, recorded_ship := fly(t, client, recorded.ships[0].Symbol)
+	recorder.Close()
+	if recorded_err == nil {
+		t.Fatal("the navigation went through while recording")
+	}
+
+	replay, replayer := replayer_client(t, cassette)
+	replay.RetryBaseDelay, replay.RetryMaxDelay = time.Millisecond, time.Millisecond
+	replayed := play(t, replay)
+	if !reflect.DeepEqual(replayed.agent, recorded.agent) {
+		t.Errorf("replayed agent %+v, recorded %+v", replayed.agent, recorded.agent)
+	}
+	replayed_err, replayed_ship := fly(t, replay, replayed.ships[0].Symbol)
+	if replayed_err == nil || errors.Is(replayed_err, spacetraders.ErrCassetteMismatch) {
+		t.Errorf("got %v navigating in the replay, want it to fail as it did when recorded", replayed_err)
+	}
+	if !reflect.DeepEqual(replayed_ship, recorded_ship) {
+		t.Errorf("replayed ship %+v, recorded %+v", replayed_ship, recorded_ship)
+	}
+	if remaining := replayer.Remaining(); remaining != 0 {
+		t.Errorf("%d interactions left on the tape", remaining)
+	}
+}
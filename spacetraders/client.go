@@ -0,0 +1,199 @@
+// Package spacetraders is a client for the SpaceTraders API, https://spacetraders.io
+package spacetraders
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const DefaultBaseURL = "https://api.spacetraders.io/v2/"
+
+// Client makes calls to the SpaceTraders API on behalf of one agent.
+// Every call waits on Limiter and is retried according to MaxAttempts.
+type Client struct {
+	BaseURL string
+	Token   string
+
+	// AccountToken is sent instead of Token to register new agents, it is on the account page at
+	// https://my.spacetraders.io
+	AccountToken string
+
+	HTTPClient *http.Client
+	Limiter    *RateLimiter
+
+	// how long a single attempt at a request may take, zero means no limit beyond the caller's context
+	RequestTimeout time.Duration
+
+	// how many times a request is sent before its last error is returned to the caller, at least once
+	MaxAttempts    int
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+
+	// print each url as it is requested
+	Debug bool
+
+	// OnTransaction, if set, is told about every transaction the game reports: buying and selling cargo,
+	// refuelling, buying ships and jumping. credits is what it did to the agent's balance, negative
+	// for spending, and agent is the agent as it was straight after.
+	OnTransaction func(ctx context.Context, transaction Transaction, credits int64, agent Agent)
+
+	calls atomic.Int64
+	skew  atomic.Int64 // see ClockSkew
+}
+
+// NewClient returns a Client for the live server. token may be empty until an agent is registered.
+func NewClient(token string) *Client {
+	return &Client{
+		BaseURL:        DefaultBaseURL,
+		Token:          token,
+		HTTPClient:     &http.Client{},
+		Limiter:        NewServerRateLimiter(),
+		RequestTimeout: 30 * time.Second,
+		MaxAttempts:    5,
+		RetryBaseDelay: 1 * time.Second,
+		RetryMaxDelay:  30 * time.Second,
+	}
+}
+
+// Calls returns how many http calls have been answered since the last ResetCalls.
+func (client *Client) Calls() int64 {
+	return client.calls.Load()
+}
+
+func (client *Client) ResetCalls() {
+	client.calls.Store(0)
+}
+
+func (client *Client) report_transaction(ctx context.Context, transaction Transaction, credits int64, agent Agent) {
+	if client.OnTransaction != nil {
+		client.OnTransaction(ctx, transaction, credits, agent)
+	}
+}
+
+func (client *Client) basic_get(ctx context.Context, endpoint string) (response_body string, err error) {
+	url := client.BaseURL + endpoint
+
+	if client.Debug {
+		fmt.Println("[DEBUG] " + url)
+	}
+
+	return client.send_with_retry(ctx, "GET", url, nil)
+}
+
+func (client *Client) basic_post(ctx context.Context, endpoint string, payload []byte) (response_body string, err error) {
+	posturl := client.BaseURL + endpoint
+
+	if client.Debug {
+		fmt.Println("[DEBUG] " + posturl)
+	}
+
+	return client.send_with_retry(ctx, "POST", posturl, payload)
+}
+
+func (client *Client) basic_patch(ctx context.Context, endpoint string, payload []byte) (response_body string, err error) {
+	patchurl := client.BaseURL + endpoint
+
+	if client.Debug {
+		fmt.Println("[DEBUG] " + patchurl)
+	}
+
+	return client.send_with_retry(ctx, "PATCH", patchurl, payload)
+}
+
+// do_request sends the request with the auth headers set and returns the body.
+// If the game answered with an error object the body is still returned, along with an *APIError.
+func (client *Client) do_request(request *http.Request) (response_body string, err error) {
+	request.Header.Add("Content-Type", "application/json")
+	token := client.Token
+	if client.AccountToken != "" && strings.HasSuffix(request.URL.Path, "/register") {
+		token = client.AccountToken
+	}
+	if token != "" {
+		request.Header.Add("Authorization", "Bearer "+token)
+	}
+	result, err := client.HTTPClient.Do(request)
+	if err != nil {
+		return "", fmt.Errorf("%s %s: %w", request.Method, request.URL, err)
+	}
+	defer result.Body.Close()
+	body, err := io.ReadAll(result.Body)
+	if err != nil {
+		return "", fmt.Errorf("%s %s: reading body: %w", request.Method, request.URL, err)
+	}
+	client.calls.Add(1)
+	client.observe_date(result.Header, time.Now())
+
+	error_container := ErrorResponse{}
+	unmarshal_err := json.Unmarshal(body, &error_container)
+
+	// any error status is an error, whether or not the body is an error object: proxies and load
+	// balancers answer with html, and not every error object has a message
+	if result.StatusCode >= 400 || error_container.Error.Message != "" {
+		api_error := &APIError{
+			StatusCode: result.StatusCode,
+			Code:       error_container.Error.Code,
+			Message:    error_container.Error.Message,
+			Data:       error_container.Error.Data,
+			RetryAfter: parse_retry_after(result.Header),
+		}
+		if api_error.Message == "" {
+			api_error.Message = http.StatusText(result.StatusCode)
+		}
+		return string(body), api_error
+	}
+	if unmarshal_err != nil {
+		return string(body), fmt.Errorf("%s %s: unmarshal http %d response: %w", request.Method, request.URL, result.StatusCode, unmarshal_err)
+	}
+
+	return string(body), nil
+}
+
+// decode_get performs a GET against endpoint and unmarshals the response into data_container.
+func (client *Client) decode_get(ctx context.Context, endpoint string, data_container interface{}) error {
+	response_string, err := client.basic_get(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(response_string), data_container); err != nil {
+		return fmt.Errorf("GET %s: unmarshal: %w", endpoint, err)
+	}
+	return nil
+}
+
+// decode_post marshals payload, POSTs it to endpoint and unmarshals the response into data_container.
+func (client *Client) decode_post(ctx context.Context, endpoint string, payload interface{}, data_container interface{}) error {
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	response_string, err := client.basic_post(ctx, endpoint, payloadJSON)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(response_string), data_container); err != nil {
+		return fmt.Errorf("POST %s: unmarshal: %w", endpoint, err)
+	}
+	return nil
+}
+
+// decode_patch marshals payload, PATCHes endpoint with it and unmarshals the response into data_container.
+func (client *Client) decode_patch(ctx context.Context, endpoint string, payload interface{}, data_container interface{}) error {
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	response_string, err := client.basic_patch(ctx, endpoint, payloadJSON)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(response_string), data_container); err != nil {
+		return fmt.Errorf("PATCH %s: unmarshal: %w", endpoint, err)
+	}
+	return nil
+}
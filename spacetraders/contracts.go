@@ -0,0 +1,63 @@
+package spacetraders
+
+import "context"
+
+func (client *Client) ListContracts(ctx context.Context) ([]Contract, error) {
+	return client.ListContractsPager(ctx).All()
+}
+
+// ListContractsPager iterates over every contract the agent has been offered, page by page.
+func (client *Client) ListContractsPager(ctx context.Context) *Pager[Contract] {
+	endpoint := "my/contracts"
+	return NewPager(func(page int64) ([]Contract, Meta, error) {
+		data_container := ListContractsResponseData{}
+		err := client.decode_get(ctx, paged_endpoint(endpoint, page), &data_container)
+		return data_container.Data, data_container.Meta, err
+	})
+}
+
+func (client *Client) GetContract(ctx context.Context, contract_id string) (Contract, error) {
+	endpoint := "my/contracts/" + contract_id
+	data_container := GetContractResponseData{}
+	err := client.decode_get(ctx, endpoint, &data_container)
+	return data_container.Data, err
+}
+
+func (client *Client) AcceptContract(ctx context.Context, contract_id string) (AcceptContractResponse, error) {
+	endpoint := "my/contracts/" + contract_id + "/accept"
+	payload := &EmptyPayload{}
+	data_container := AcceptContractResponseData{}
+	err := client.decode_post(ctx, endpoint, payload, &data_container)
+	return data_container.Data, err
+}
+
+// DeliverContract hands units of trade_symbol from the ship's hold over to the contract.
+// The ship must be docked at the delivery destination.
+func (client *Client) DeliverContract(ctx context.Context, contract_id string, ship_symbol string, trade_symbol TradeSymbol, units int64) (DeliverContractResponse, error) {
+	endpoint := "my/contracts/" + contract_id + "/deliver"
+	payload := &DeliverContractPayload{}
+	payload.ShipSymbol = ship_symbol
+	payload.TradeSymbol = trade_symbol
+	payload.Units = units
+	data_container := DeliverContractResponseData{}
+	err := client.decode_post(ctx, endpoint, payload, &data_container)
+	return data_container.Data, err
+}
+
+func (client *Client) FulfillContract(ctx context.Context, contract_id string) (FulfillContractResponse, error) {
+	endpoint := "my/contracts/" + contract_id + "/fulfill"
+	payload := &EmptyPayload{}
+	data_container := FulfillContractResponseData{}
+	err := client.decode_post(ctx, endpoint, payload, &data_container)
+	return data_container.Data, err
+}
+
+// NegotiateContract asks the faction at the ship's current waypoint for a new contract.
+// The ship must be docked at a waypoint belonging to a faction.
+func (client *Client) NegotiateContract(ctx context.Context, ship_symbol string) (Contract, error) {
+	endpoint := "my/ships/" + ship_symbol + "/negotiate/contract"
+	payload := &EmptyPayload{}
+	data_container := NegotiateContractResponseData{}
+	err := client.decode_post(ctx, endpoint, payload, &data_container)
+	return data_container.Data.Contract, err
+}
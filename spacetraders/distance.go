@@ -0,0 +1,18 @@
+package spacetraders
+
+import "math"
+
+func DistanceBetweenTwoCoordinates(waypoint1X int64, waypoint1Y int64, waypoint2X int64, waypoint2Y int64) (resultant_distance float64) {
+	XIntermediate := waypoint1X - waypoint2X
+	YIntermediate := waypoint1Y - waypoint2Y
+	XSquared := XIntermediate * XIntermediate
+	YSquared := YIntermediate * YIntermediate
+	XPlusY := XSquared + YSquared
+	XPlusYFloat := float64(XPlusY)
+	resultant_distance = math.Sqrt(XPlusYFloat)
+	return resultant_distance
+}
+
+func DistanceBetweenTwoWaypoints(waypoint1 Waypoint, waypoint2 Waypoint) float64 {
+	return DistanceBetweenTwoCoordinates(waypoint1.X, waypoint1.Y, waypoint2.X, waypoint2.Y)
+}
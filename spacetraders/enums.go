@@ -0,0 +1,859 @@
+package spacetraders
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// OnUnknownValue is called the first time the server sends a value one of the types below has no
+// constant for, usually because the game has added something since. The value is kept as it is.
+var OnUnknownValue = func(kind string, value string) {
+	fmt.Println("[INFO] Unknown " + kind + " " + value + " from the server")
+}
+
+// the values already passed to OnUnknownValue, so each is only reported once
+var unknown_values sync.Map
+
+func known_values[T ~string](values ...T) map[T]bool {
+	known := make(map[T]bool, len(values))
+	for _, value := range values {
+		known[value] = true
+	}
+	return known
+}
+
+// unmarshal_enum decodes a string into value, reporting it if it is not one of the known values.
+// An empty string is taken as left out rather than unknown.
+func unmarshal_enum[T ~string](data []byte, kind string, value *T, known map[T]bool) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return fmt.Errorf("%s: %w", kind, err)
+	}
+	*value = T(text)
+	if text != "" && !known[*value] {
+		if _, reported := unknown_values.LoadOrStore(kind+" "+text, true); !reported {
+			OnUnknownValue(kind, text)
+		}
+	}
+	return nil
+}
+
+// NavStatus is where a ship is: on its way somewhere, in orbit or docked.
+type NavStatus string
+
+const (
+	NavStatusInTransit NavStatus = "IN_TRANSIT"
+	NavStatusInOrbit   NavStatus = "IN_ORBIT"
+	NavStatusDocked    NavStatus = "DOCKED"
+)
+
+var nav_statuses = known_values(
+	NavStatusInTransit,
+	NavStatusInOrbit,
+	NavStatusDocked,
+)
+
+// Valid is true if status is one the game is known to use.
+func (status NavStatus) Valid() bool {
+	return nav_statuses[status]
+}
+
+func (status *NavStatus) UnmarshalJSON(data []byte) error {
+	return unmarshal_enum(data, "nav status", status, nav_statuses)
+}
+
+// FlightMode trades a ship's speed against the fuel it burns.
+type FlightMode string
+
+const (
+	FlightModeDrift   FlightMode = "DRIFT"
+	FlightModeStealth FlightMode = "STEALTH"
+	FlightModeCruise  FlightMode = "CRUISE"
+	FlightModeBurn    FlightMode = "BURN"
+)
+
+var flight_modes = known_values(
+	FlightModeDrift,
+	FlightModeStealth,
+	FlightModeCruise,
+	FlightModeBurn,
+)
+
+// Valid is true if mode is one the game is known to use.
+func (mode FlightMode) Valid() bool {
+	return flight_modes[mode]
+}
+
+func (mode *FlightMode) UnmarshalJSON(data []byte) error {
+	return unmarshal_enum(data, "flight mode", mode, flight_modes)
+}
+
+// ShipRole is what the game registered a ship as, COMMAND for the one every agent starts with.
+type ShipRole string
+
+const (
+	ShipRoleFabricator  ShipRole = "FABRICATOR"
+	ShipRoleHarvester   ShipRole = "HARVESTER"
+	ShipRoleHauler      ShipRole = "HAULER"
+	ShipRoleInterceptor ShipRole = "INTERCEPTOR"
+	ShipRoleExcavator   ShipRole = "EXCAVATOR"
+	ShipRoleTransport   ShipRole = "TRANSPORT"
+	ShipRoleRepair      ShipRole = "REPAIR"
+	ShipRoleSurveyor    ShipRole = "SURVEYOR"
+	ShipRoleCommand     ShipRole = "COMMAND"
+	ShipRoleCarrier     ShipRole = "CARRIER"
+	ShipRolePatrol      ShipRole = "PATROL"
+	ShipRoleSatellite   ShipRole = "SATELLITE"
+	ShipRoleExplorer    ShipRole = "EXPLORER"
+	ShipRoleRefinery    ShipRole = "REFINERY"
+)
+
+var ship_roles = known_values(
+	ShipRoleFabricator,
+	ShipRoleHarvester,
+	ShipRoleHauler,
+	ShipRoleInterceptor,
+	ShipRoleExcavator,
+	ShipRoleTransport,
+	ShipRoleRepair,
+	ShipRoleSurveyor,
+	ShipRoleCommand,
+	ShipRoleCarrier,
+	ShipRolePatrol,
+	ShipRoleSatellite,
+	ShipRoleExplorer,
+	ShipRoleRefinery,
+)
+
+// Valid is true if role is one the game is known to use.
+func (role ShipRole) Valid() bool {
+	return ship_roles[role]
+}
+
+func (role *ShipRole) UnmarshalJSON(data []byte) error {
+	return unmarshal_enum(data, "ship role", role, ship_roles)
+}
+
+// WaypointType is what kind of place a waypoint is.
+type WaypointType string
+
+const (
+	WaypointTypePlanet                WaypointType = "PLANET"
+	WaypointTypeGasGiant              WaypointType = "GAS_GIANT"
+	WaypointTypeMoon                  WaypointType = "MOON"
+	WaypointTypeOrbitalStation        WaypointType = "ORBITAL_STATION"
+	WaypointTypeJumpGate              WaypointType = "JUMP_GATE"
+	WaypointTypeAsteroidField         WaypointType = "ASTEROID_FIELD"
+	WaypointTypeAsteroid              WaypointType = "ASTEROID"
+	WaypointTypeEngineeredAsteroid    WaypointType = "ENGINEERED_ASTEROID"
+	WaypointTypeAsteroidBase          WaypointType = "ASTEROID_BASE"
+	WaypointTypeNebula                WaypointType = "NEBULA"
+	WaypointTypeDebrisField           WaypointType = "DEBRIS_FIELD"
+	WaypointTypeGravityWell           WaypointType = "GRAVITY_WELL"
+	WaypointTypeArtificialGravityWell WaypointType = "ARTIFICIAL_GRAVITY_WELL"
+	WaypointTypeFuelStation           WaypointType = "FUEL_STATION"
+)
+
+var waypoint_types = known_values(
+	WaypointTypePlanet,
+	WaypointTypeGasGiant,
+	WaypointTypeMoon,
+	WaypointTypeOrbitalStation,
+	WaypointTypeJumpGate,
+	WaypointTypeAsteroidField,
+	WaypointTypeAsteroid,
+	WaypointTypeEngineeredAsteroid,
+	WaypointTypeAsteroidBase,
+	WaypointTypeNebula,
+	WaypointTypeDebrisField,
+	WaypointTypeGravityWell,
+	WaypointTypeArtificialGravityWell,
+	WaypointTypeFuelStation,
+)
+
+// Valid is true if waypoint_type is one the game is known to use.
+func (waypoint_type WaypointType) Valid() bool {
+	return waypoint_types[waypoint_type]
+}
+
+func (waypoint_type *WaypointType) UnmarshalJSON(data []byte) error {
+	return unmarshal_enum(data, "waypoint type", waypoint_type, waypoint_types)
+}
+
+// WaypointTrait is something about a waypoint, such as it having a MARKETPLACE or a SHIPYARD.
+type WaypointTrait string
+
+const (
+	WaypointTraitUncharted             WaypointTrait = "UNCHARTED"
+	WaypointTraitUnderConstruction     WaypointTrait = "UNDER_CONSTRUCTION"
+	WaypointTraitMarketplace           WaypointTrait = "MARKETPLACE"
+	WaypointTraitShipyard              WaypointTrait = "SHIPYARD"
+	WaypointTraitOutpost               WaypointTrait = "OUTPOST"
+	WaypointTraitScatteredSettlements  WaypointTrait = "SCATTERED_SETTLEMENTS"
+	WaypointTraitSprawlingCities       WaypointTrait = "SPRAWLING_CITIES"
+	WaypointTraitMegaStructures        WaypointTrait = "MEGA_STRUCTURES"
+	WaypointTraitPirateBase            WaypointTrait = "PIRATE_BASE"
+	WaypointTraitOvercrowded           WaypointTrait = "OVERCROWDED"
+	WaypointTraitHighTech              WaypointTrait = "HIGH_TECH"
+	WaypointTraitCorrupt               WaypointTrait = "CORRUPT"
+	WaypointTraitBureaucratic          WaypointTrait = "BUREAUCRATIC"
+	WaypointTraitTradingHub            WaypointTrait = "TRADING_HUB"
+	WaypointTraitIndustrial            WaypointTrait = "INDUSTRIAL"
+	WaypointTraitBlackMarket           WaypointTrait = "BLACK_MARKET"
+	WaypointTraitResearchFacility      WaypointTrait = "RESEARCH_FACILITY"
+	WaypointTraitMilitaryBase          WaypointTrait = "MILITARY_BASE"
+	WaypointTraitSurveillanceOutpost   WaypointTrait = "SURVEILLANCE_OUTPOST"
+	WaypointTraitExplorationOutpost    WaypointTrait = "EXPLORATION_OUTPOST"
+	WaypointTraitMineralDeposits       WaypointTrait = "MINERAL_DEPOSITS"
+	WaypointTraitCommonMetalDeposits   WaypointTrait = "COMMON_METAL_DEPOSITS"
+	WaypointTraitPreciousMetalDeposits WaypointTrait = "PRECIOUS_METAL_DEPOSITS"
+	WaypointTraitRareMetalDeposits     WaypointTrait = "RARE_METAL_DEPOSITS"
+	WaypointTraitMethanePools          WaypointTrait = "METHANE_POOLS"
+	WaypointTraitIceCrystals           WaypointTrait = "ICE_CRYSTALS"
+	WaypointTraitExplosiveGases        WaypointTrait = "EXPLOSIVE_GASES"
+	WaypointTraitStrongMagnetosphere   WaypointTrait = "STRONG_MAGNETOSPHERE"
+	WaypointTraitVibrantAuroras        WaypointTrait = "VIBRANT_AURORAS"
+	WaypointTraitSaltFlats             WaypointTrait = "SALT_FLATS"
+	WaypointTraitCanyons               WaypointTrait = "CANYONS"
+	WaypointTraitPerpetualDaylight     WaypointTrait = "PERPETUAL_DAYLIGHT"
+	WaypointTraitPerpetualOvercast     WaypointTrait = "PERPETUAL_OVERCAST"
+	WaypointTraitDrySeabeds            WaypointTrait = "DRY_SEABEDS"
+	WaypointTraitMagmaSeas             WaypointTrait = "MAGMA_SEAS"
+	WaypointTraitSupervolcanoes        WaypointTrait = "SUPERVOLCANOES"
+	WaypointTraitAshClouds             WaypointTrait = "ASH_CLOUDS"
+	WaypointTraitVastRuins             WaypointTrait = "VAST_RUINS"
+	WaypointTraitMutatedFlora          WaypointTrait = "MUTATED_FLORA"
+	WaypointTraitTerraformed           WaypointTrait = "TERRAFORMED"
+	WaypointTraitExtremeTemperatures   WaypointTrait = "EXTREME_TEMPERATURES"
+	WaypointTraitExtremePressure       WaypointTrait = "EXTREME_PRESSURE"
+	WaypointTraitDiverseLife           WaypointTrait = "DIVERSE_LIFE"
+	WaypointTraitScarceLife            WaypointTrait = "SCARCE_LIFE"
+	WaypointTraitFossils               WaypointTrait = "FOSSILS"
+	WaypointTraitWeakGravity           WaypointTrait = "WEAK_GRAVITY"
+	WaypointTraitStrongGravity         WaypointTrait = "STRONG_GRAVITY"
+	WaypointTraitCrushingGravity       WaypointTrait = "CRUSHING_GRAVITY"
+	WaypointTraitToxicAtmosphere       WaypointTrait = "TOXIC_ATMOSPHERE"
+	WaypointTraitCorrosiveAtmosphere   WaypointTrait = "CORROSIVE_ATMOSPHERE"
+	WaypointTraitBreathableAtmosphere  WaypointTrait = "BREATHABLE_ATMOSPHERE"
+	WaypointTraitThinAtmosphere        WaypointTrait = "THIN_ATMOSPHERE"
+	WaypointTraitJovian                WaypointTrait = "JOVIAN"
+	WaypointTraitRocky                 WaypointTrait = "ROCKY"
+	WaypointTraitVolcanic              WaypointTrait = "VOLCANIC"
+	WaypointTraitFrozen                WaypointTrait = "FROZEN"
+	WaypointTraitSwamp                 WaypointTrait = "SWAMP"
+	WaypointTraitBarren                WaypointTrait = "BARREN"
+	WaypointTraitTemperate             WaypointTrait = "TEMPERATE"
+	WaypointTraitJungle                WaypointTrait = "JUNGLE"
+	WaypointTraitOcean                 WaypointTrait = "OCEAN"
+	WaypointTraitRadioactive           WaypointTrait = "RADIOACTIVE"
+	WaypointTraitMicroGravityAnomalies WaypointTrait = "MICRO_GRAVITY_ANOMALIES"
+	WaypointTraitDebrisCluster         WaypointTrait = "DEBRIS_CLUSTER"
+	WaypointTraitDeepCraters           WaypointTrait = "DEEP_CRATERS"
+	WaypointTraitShallowCraters        WaypointTrait = "SHALLOW_CRATERS"
+	WaypointTraitUnstableComposition   WaypointTrait = "UNSTABLE_COMPOSITION"
+	WaypointTraitHollowedInterior      WaypointTrait = "HOLLOWED_INTERIOR"
+	WaypointTraitStripped              WaypointTrait = "STRIPPED"
+)
+
+var waypoint_traits = known_values(
+	WaypointTraitUncharted,
+	WaypointTraitUnderConstruction,
+	WaypointTraitMarketplace,
+	WaypointTraitShipyard,
+	WaypointTraitOutpost,
+	WaypointTraitScatteredSettlements,
+	WaypointTraitSprawlingCities,
+	WaypointTraitMegaStructures,
+	WaypointTraitPirateBase,
+	WaypointTraitOvercrowded,
+	WaypointTraitHighTech,
+	WaypointTraitCorrupt,
+	WaypointTraitBureaucratic,
+	WaypointTraitTradingHub,
+	WaypointTraitIndustrial,
+	WaypointTraitBlackMarket,
+	WaypointTraitResearchFacility,
+	WaypointTraitMilitaryBase,
+	WaypointTraitSurveillanceOutpost,
+	WaypointTraitExplorationOutpost,
+	WaypointTraitMineralDeposits,
+	WaypointTraitCommonMetalDeposits,
+	WaypointTraitPreciousMetalDeposits,
+	WaypointTraitRareMetalDeposits,
+	WaypointTraitMethanePools,
+	WaypointTraitIceCrystals,
+	WaypointTraitExplosiveGases,
+	WaypointTraitStrongMagnetosphere,
+	WaypointTraitVibrantAuroras,
+	WaypointTraitSaltFlats,
+	WaypointTraitCanyons,
+	WaypointTraitPerpetualDaylight,
+	WaypointTraitPerpetualOvercast,
+	WaypointTraitDrySeabeds,
+	WaypointTraitMagmaSeas,
+	WaypointTraitSupervolcanoes,
+	WaypointTraitAshClouds,
+	WaypointTraitVastRuins,
+	WaypointTraitMutatedFlora,
+	WaypointTraitTerraformed,
+	WaypointTraitExtremeTemperatures,
+	WaypointTraitExtremePressure,
+	WaypointTraitDiverseLife,
+	WaypointTraitScarceLife,
+	WaypointTraitFossils,
+	WaypointTraitWeakGravity,
+	WaypointTraitStrongGravity,
+	WaypointTraitCrushingGravity,
+	WaypointTraitToxicAtmosphere,
+	WaypointTraitCorrosiveAtmosphere,
+	WaypointTraitBreathableAtmosphere,
+	WaypointTraitThinAtmosphere,
+	WaypointTraitJovian,
+	WaypointTraitRocky,
+	WaypointTraitVolcanic,
+	WaypointTraitFrozen,
+	WaypointTraitSwamp,
+	WaypointTraitBarren,
+	WaypointTraitTemperate,
+	WaypointTraitJungle,
+	WaypointTraitOcean,
+	WaypointTraitRadioactive,
+	WaypointTraitMicroGravityAnomalies,
+	WaypointTraitDebrisCluster,
+	WaypointTraitDeepCraters,
+	WaypointTraitShallowCraters,
+	WaypointTraitUnstableComposition,
+	WaypointTraitHollowedInterior,
+	WaypointTraitStripped,
+)
+
+// Valid is true if trait is one the game is known to use.
+func (trait WaypointTrait) Valid() bool {
+	return waypoint_traits[trait]
+}
+
+func (trait *WaypointTrait) UnmarshalJSON(data []byte) error {
+	return unmarshal_enum(data, "waypoint trait", trait, waypoint_traits)
+}
+
+// TradeSymbol is a good that can be bought, sold, mined or carried.
+type TradeSymbol string
+
+const (
+	TradeSymbolPreciousStones          TradeSymbol = "PRECIOUS_STONES"
+	TradeSymbolQuartzSand              TradeSymbol = "QUARTZ_SAND"
+	TradeSymbolSiliconCrystals         TradeSymbol = "SILICON_CRYSTALS"
+	TradeSymbolAmmoniaIce              TradeSymbol = "AMMONIA_ICE"
+	TradeSymbolLiquidHydrogen          TradeSymbol = "LIQUID_HYDROGEN"
+	TradeSymbolLiquidNitrogen          TradeSymbol = "LIQUID_NITROGEN"
+	TradeSymbolIceWater                TradeSymbol = "ICE_WATER"
+	TradeSymbolExoticMatter            TradeSymbol = "EXOTIC_MATTER"
+	TradeSymbolAdvancedCircuitry       TradeSymbol = "ADVANCED_CIRCUITRY"
+	TradeSymbolGravitonEmitters        TradeSymbol = "GRAVITON_EMITTERS"
+	TradeSymbolIron                    TradeSymbol = "IRON"
+	TradeSymbolIronOre                 TradeSymbol = "IRON_ORE"
+	TradeSymbolCopper                  TradeSymbol = "COPPER"
+	TradeSymbolCopperOre               TradeSymbol = "COPPER_ORE"
+	TradeSymbolAluminum                TradeSymbol = "ALUMINUM"
+	TradeSymbolAluminumOre             TradeSymbol = "ALUMINUM_ORE"
+	TradeSymbolSilver                  TradeSymbol = "SILVER"
+	TradeSymbolSilverOre               TradeSymbol = "SILVER_ORE"
+	TradeSymbolGold                    TradeSymbol = "GOLD"
+	TradeSymbolGoldOre                 TradeSymbol = "GOLD_ORE"
+	TradeSymbolPlatinum                TradeSymbol = "PLATINUM"
+	TradeSymbolPlatinumOre             TradeSymbol = "PLATINUM_ORE"
+	TradeSymbolDiamonds                TradeSymbol = "DIAMONDS"
+	TradeSymbolUranite                 TradeSymbol = "URANITE"
+	TradeSymbolUraniteOre              TradeSymbol = "URANITE_ORE"
+	TradeSymbolMeritium                TradeSymbol = "MERITIUM"
+	TradeSymbolMeritiumOre             TradeSymbol = "MERITIUM_ORE"
+	TradeSymbolHydrocarbon             TradeSymbol = "HYDROCARBON"
+	TradeSymbolAntimatter              TradeSymbol = "ANTIMATTER"
+	TradeSymbolFabMats                 TradeSymbol = "FAB_MATS"
+	TradeSymbolFertilizers             TradeSymbol = "FERTILIZERS"
+	TradeSymbolFabrics                 TradeSymbol = "FABRICS"
+	TradeSymbolFood                    TradeSymbol = "FOOD"
+	TradeSymbolJewelry                 TradeSymbol = "JEWELRY"
+	TradeSymbolMachinery               TradeSymbol = "MACHINERY"
+	TradeSymbolFirearms                TradeSymbol = "FIREARMS"
+	TradeSymbolAssaultRifles           TradeSymbol = "ASSAULT_RIFLES"
+	TradeSymbolMilitaryEquipment       TradeSymbol = "MILITARY_EQUIPMENT"
+	TradeSymbolExplosives              TradeSymbol = "EXPLOSIVES"
+	TradeSymbolLabInstruments          TradeSymbol = "LAB_INSTRUMENTS"
+	TradeSymbolAmmunition              TradeSymbol = "AMMUNITION"
+	TradeSymbolElectronics             TradeSymbol = "ELECTRONICS"
+	TradeSymbolShipPlating             TradeSymbol = "SHIP_PLATING"
+	TradeSymbolShipParts               TradeSymbol = "SHIP_PARTS"
+	TradeSymbolEquipment               TradeSymbol = "EQUIPMENT"
+	TradeSymbolFuel                    TradeSymbol = "FUEL"
+	TradeSymbolMedicine                TradeSymbol = "MEDICINE"
+	TradeSymbolDrugs                   TradeSymbol = "DRUGS"
+	TradeSymbolClothing                TradeSymbol = "CLOTHING"
+	TradeSymbolMicroprocessors         TradeSymbol = "MICROPROCESSORS"
+	TradeSymbolPlastics                TradeSymbol = "PLASTICS"
+	TradeSymbolPolynucleotides         TradeSymbol = "POLYNUCLEOTIDES"
+	TradeSymbolBiocomposites           TradeSymbol = "BIOCOMPOSITES"
+	TradeSymbolQuantumStabilizers      TradeSymbol = "QUANTUM_STABILIZERS"
+	TradeSymbolNanobots                TradeSymbol = "NANOBOTS"
+	TradeSymbolAIMainframes            TradeSymbol = "AI_MAINFRAMES"
+	TradeSymbolQuantumDrives           TradeSymbol = "QUANTUM_DRIVES"
+	TradeSymbolRoboticDrones           TradeSymbol = "ROBOTIC_DRONES"
+	TradeSymbolCyberImplants           TradeSymbol = "CYBER_IMPLANTS"
+	TradeSymbolGeneTherapeutics        TradeSymbol = "GENE_THERAPEUTICS"
+	TradeSymbolNeuralChips             TradeSymbol = "NEURAL_CHIPS"
+	TradeSymbolMoodRegulators          TradeSymbol = "MOOD_REGULATORS"
+	TradeSymbolViralAgents             TradeSymbol = "VIRAL_AGENTS"
+	TradeSymbolMicroFusionGenerators   TradeSymbol = "MICRO_FUSION_GENERATORS"
+	TradeSymbolSupergrains             TradeSymbol = "SUPERGRAINS"
+	TradeSymbolLaserRifles             TradeSymbol = "LASER_RIFLES"
+	TradeSymbolHolographics            TradeSymbol = "HOLOGRAPHICS"
+	TradeSymbolShipSalvage             TradeSymbol = "SHIP_SALVAGE"
+	TradeSymbolRelicTech               TradeSymbol = "RELIC_TECH"
+	TradeSymbolNovelLifeforms          TradeSymbol = "NOVEL_LIFEFORMS"
+	TradeSymbolBotanicalSpecimens      TradeSymbol = "BOTANICAL_SPECIMENS"
+	TradeSymbolCulturalArtifacts       TradeSymbol = "CULTURAL_ARTIFACTS"
+	TradeSymbolFrameProbe              TradeSymbol = "FRAME_PROBE"
+	TradeSymbolFrameDrone              TradeSymbol = "FRAME_DRONE"
+	TradeSymbolFrameInterceptor        TradeSymbol = "FRAME_INTERCEPTOR"
+	TradeSymbolFrameRacer              TradeSymbol = "FRAME_RACER"
+	TradeSymbolFrameFighter            TradeSymbol = "FRAME_FIGHTER"
+	TradeSymbolFrameFrigate            TradeSymbol = "FRAME_FRIGATE"
+	TradeSymbolFrameShuttle            TradeSymbol = "FRAME_SHUTTLE"
+	TradeSymbolFrameExplorer           TradeSymbol = "FRAME_EXPLORER"
+	TradeSymbolFrameMiner              TradeSymbol = "FRAME_MINER"
+	TradeSymbolFrameLightFreighter     TradeSymbol = "FRAME_LIGHT_FREIGHTER"
+	TradeSymbolFrameHeavyFreighter     TradeSymbol = "FRAME_HEAVY_FREIGHTER"
+	TradeSymbolFrameTransport          TradeSymbol = "FRAME_TRANSPORT"
+	TradeSymbolFrameDestroyer          TradeSymbol = "FRAME_DESTROYER"
+	TradeSymbolFrameCruiser            TradeSymbol = "FRAME_CRUISER"
+	TradeSymbolFrameCarrier            TradeSymbol = "FRAME_CARRIER"
+	TradeSymbolFrameBulkFreighter      TradeSymbol = "FRAME_BULK_FREIGHTER"
+	TradeSymbolReactorSolarI           TradeSymbol = "REACTOR_SOLAR_I"
+	TradeSymbolReactorFusionI          TradeSymbol = "REACTOR_FUSION_I"
+	TradeSymbolReactorFissionI         TradeSymbol = "REACTOR_FISSION_I"
+	TradeSymbolReactorChemicalI        TradeSymbol = "REACTOR_CHEMICAL_I"
+	TradeSymbolReactorAntimatterI      TradeSymbol = "REACTOR_ANTIMATTER_I"
+	TradeSymbolEngineImpulseDriveI     TradeSymbol = "ENGINE_IMPULSE_DRIVE_I"
+	TradeSymbolEngineIonDriveI         TradeSymbol = "ENGINE_ION_DRIVE_I"
+	TradeSymbolEngineIonDriveIi        TradeSymbol = "ENGINE_ION_DRIVE_II"
+	TradeSymbolEngineHyperDriveI       TradeSymbol = "ENGINE_HYPER_DRIVE_I"
+	TradeSymbolModuleMineralProcessorI TradeSymbol = "MODULE_MINERAL_PROCESSOR_I"
+	TradeSymbolModuleGasProcessorI     TradeSymbol = "MODULE_GAS_PROCESSOR_I"
+	TradeSymbolModuleCargoHoldI        TradeSymbol = "MODULE_CARGO_HOLD_I"
+	TradeSymbolModuleCargoHoldIi       TradeSymbol = "MODULE_CARGO_HOLD_II"
+	TradeSymbolModuleCargoHoldIii      TradeSymbol = "MODULE_CARGO_HOLD_III"
+	TradeSymbolModuleCrewQuartersI     TradeSymbol = "MODULE_CREW_QUARTERS_I"
+	TradeSymbolModuleEnvoyQuartersI    TradeSymbol = "MODULE_ENVOY_QUARTERS_I"
+	TradeSymbolModulePassengerCabinI   TradeSymbol = "MODULE_PASSENGER_CABIN_I"
+	TradeSymbolModuleMicroRefineryI    TradeSymbol = "MODULE_MICRO_REFINERY_I"
+	TradeSymbolModuleScienceLabI       TradeSymbol = "MODULE_SCIENCE_LAB_I"
+	TradeSymbolModuleJumpDriveI        TradeSymbol = "MODULE_JUMP_DRIVE_I"
+	TradeSymbolModuleJumpDriveIi       TradeSymbol = "MODULE_JUMP_DRIVE_II"
+	TradeSymbolModuleJumpDriveIii      TradeSymbol = "MODULE_JUMP_DRIVE_III"
+	TradeSymbolModuleWarpDriveI        TradeSymbol = "MODULE_WARP_DRIVE_I"
+	TradeSymbolModuleWarpDriveIi       TradeSymbol = "MODULE_WARP_DRIVE_II"
+	TradeSymbolModuleWarpDriveIii      TradeSymbol = "MODULE_WARP_DRIVE_III"
+	TradeSymbolModuleShieldGeneratorI  TradeSymbol = "MODULE_SHIELD_GENERATOR_I"
+	TradeSymbolModuleShieldGeneratorIi TradeSymbol = "MODULE_SHIELD_GENERATOR_II"
+	TradeSymbolModuleOreRefineryI      TradeSymbol = "MODULE_ORE_REFINERY_I"
+	TradeSymbolModuleFuelRefineryI     TradeSymbol = "MODULE_FUEL_REFINERY_I"
+	TradeSymbolMountGasSiphonI         TradeSymbol = "MOUNT_GAS_SIPHON_I"
+	TradeSymbolMountGasSiphonIi        TradeSymbol = "MOUNT_GAS_SIPHON_II"
+	TradeSymbolMountGasSiphonIii       TradeSymbol = "MOUNT_GAS_SIPHON_III"
+	TradeSymbolMountSurveyorI          TradeSymbol = "MOUNT_SURVEYOR_I"
+	TradeSymbolMountSurveyorIi         TradeSymbol = "MOUNT_SURVEYOR_II"
+	TradeSymbolMountSurveyorIii        TradeSymbol = "MOUNT_SURVEYOR_III"
+	TradeSymbolMountSensorArrayI       TradeSymbol = "MOUNT_SENSOR_ARRAY_I"
+	TradeSymbolMountSensorArrayIi      TradeSymbol = "MOUNT_SENSOR_ARRAY_II"
+	TradeSymbolMountSensorArrayIii     TradeSymbol = "MOUNT_SENSOR_ARRAY_III"
+	TradeSymbolMountMiningLaserI       TradeSymbol = "MOUNT_MINING_LASER_I"
+	TradeSymbolMountMiningLaserIi      TradeSymbol = "MOUNT_MINING_LASER_II"
+	TradeSymbolMountMiningLaserIii     TradeSymbol = "MOUNT_MINING_LASER_III"
+	TradeSymbolMountLaserCannonI       TradeSymbol = "MOUNT_LASER_CANNON_I"
+	TradeSymbolMountMissileLauncherI   TradeSymbol = "MOUNT_MISSILE_LAUNCHER_I"
+	TradeSymbolMountTurretI            TradeSymbol = "MOUNT_TURRET_I"
+	TradeSymbolShipProbe               TradeSymbol = "SHIP_PROBE"
+	TradeSymbolShipMiningDrone         TradeSymbol = "SHIP_MINING_DRONE"
+	TradeSymbolShipSiphonDrone         TradeSymbol = "SHIP_SIPHON_DRONE"
+	TradeSymbolShipInterceptor         TradeSymbol = "SHIP_INTERCEPTOR"
+	TradeSymbolShipLightHauler         TradeSymbol = "SHIP_LIGHT_HAULER"
+	TradeSymbolShipCommandFrigate      TradeSymbol = "SHIP_COMMAND_FRIGATE"
+	TradeSymbolShipExplorer            TradeSymbol = "SHIP_EXPLORER"
+	TradeSymbolShipHeavyFreighter      TradeSymbol = "SHIP_HEAVY_FREIGHTER"
+	TradeSymbolShipLightShuttle        TradeSymbol = "SHIP_LIGHT_SHUTTLE"
+	TradeSymbolShipOreHound            TradeSymbol = "SHIP_ORE_HOUND"
+	TradeSymbolShipRefiningFreighter   TradeSymbol = "SHIP_REFINING_FREIGHTER"
+	TradeSymbolShipSurveyor            TradeSymbol = "SHIP_SURVEYOR"
+	TradeSymbolShipBulkFreighter       TradeSymbol = "SHIP_BULK_FREIGHTER"
+)
+
+var trade_symbols = known_values(
+	TradeSymbolPreciousStones,
+	TradeSymbolQuartzSand,
+	TradeSymbolSiliconCrystals,
+	TradeSymbolAmmoniaIce,
+	TradeSymbolLiquidHydrogen,
+	TradeSymbolLiquidNitrogen,
+	TradeSymbolIceWater,
+	TradeSymbolExoticMatter,
+	TradeSymbolAdvancedCircuitry,
+	TradeSymbolGravitonEmitters,
+	TradeSymbolIron,
+	TradeSymbolIronOre,
+	TradeSymbolCopper,
+	TradeSymbolCopperOre,
+	TradeSymbolAluminum,
+	TradeSymbolAluminumOre,
+	TradeSymbolSilver,
+	TradeSymbolSilverOre,
+	TradeSymbolGold,
+	TradeSymbolGoldOre,
+	TradeSymbolPlatinum,
+	TradeSymbolPlatinumOre,
+	TradeSymbolDiamonds,
+	TradeSymbolUranite,
+	TradeSymbolUraniteOre,
+	TradeSymbolMeritium,
+	TradeSymbolMeritiumOre,
+	TradeSymbolHydrocarbon,
+	TradeSymbolAntimatter,
+	TradeSymbolFabMats,
+	TradeSymbolFertilizers,
+	TradeSymbolFabrics,
+	TradeSymbolFood,
+	TradeSymbolJewelry,
+	TradeSymbolMachinery,
+	TradeSymbolFirearms,
+	TradeSymbolAssaultRifles,
+	TradeSymbolMilitaryEquipment,
+	TradeSymbolExplosives,
+	TradeSymbolLabInstruments,
+	TradeSymbolAmmunition,
+	TradeSymbolElectronics,
+	TradeSymbolShipPlating,
+	TradeSymbolShipParts,
+	TradeSymbolEquipment,
+	TradeSymbolFuel,
+	TradeSymbolMedicine,
+	TradeSymbolDrugs,
+	TradeSymbolClothing,
+	TradeSymbolMicroprocessors,
+	TradeSymbolPlastics,
+	TradeSymbolPolynucleotides,
+	TradeSymbolBiocomposites,
+	TradeSymbolQuantumStabilizers,
+	TradeSymbolNanobots,
+	TradeSymbolAIMainframes,
+	TradeSymbolQuantumDrives,
+	TradeSymbolRoboticDrones,
+	TradeSymbolCyberImplants,
+	TradeSymbolGeneTherapeutics,
+	TradeSymbolNeuralChips,
+	TradeSymbolMoodRegulators,
+	TradeSymbolViralAgents,
+	TradeSymbolMicroFusionGenerators,
+	TradeSymbolSupergrains,
+	TradeSymbolLaserRifles,
+	TradeSymbolHolographics,
+	TradeSymbolShipSalvage,
+	TradeSymbolRelicTech,
+	TradeSymbolNovelLifeforms,
+	TradeSymbolBotanicalSpecimens,
+	TradeSymbolCulturalArtifacts,
+	TradeSymbolFrameProbe,
+	TradeSymbolFrameDrone,
+	TradeSymbolFrameInterceptor,
+	TradeSymbolFrameRacer,
+	TradeSymbolFrameFighter,
+	TradeSymbolFrameFrigate,
+	TradeSymbolFrameShuttle,
+	TradeSymbolFrameExplorer,
+	TradeSymbolFrameMiner,
+	TradeSymbolFrameLightFreighter,
+	TradeSymbolFrameHeavyFreighter,
+	TradeSymbolFrameTransport,
+	TradeSymbolFrameDestroyer,
+	TradeSymbolFrameCruiser,
+	TradeSymbolFrameCarrier,
+	TradeSymbolFrameBulkFreighter,
+	TradeSymbolReactorSolarI,
+	TradeSymbolReactorFusionI,
+	TradeSymbolReactorFissionI,
+	TradeSymbolReactorChemicalI,
+	TradeSymbolReactorAntimatterI,
+	TradeSymbolEngineImpulseDriveI,
+	TradeSymbolEngineIonDriveI,
+	TradeSymbolEngineIonDriveIi,
+	TradeSymbolEngineHyperDriveI,
+	TradeSymbolModuleMineralProcessorI,
+	TradeSymbolModuleGasProcessorI,
+	TradeSymbolModuleCargoHoldI,
+	TradeSymbolModuleCargoHoldIi,
+	TradeSymbolModuleCargoHoldIii,
+	TradeSymbolModuleCrewQuartersI,
+	TradeSymbolModuleEnvoyQuartersI,
+	TradeSymbolModulePassengerCabinI,
+	TradeSymbolModuleMicroRefineryI,
+	TradeSymbolModuleScienceLabI,
+	TradeSymbolModuleJumpDriveI,
+	TradeSymbolModuleJumpDriveIi,
+	TradeSymbolModuleJumpDriveIii,
+	TradeSymbolModuleWarpDriveI,
+	TradeSymbolModuleWarpDriveIi,
+	TradeSymbolModuleWarpDriveIii,
+	TradeSymbolModuleShieldGeneratorI,
+	TradeSymbolModuleShieldGeneratorIi,
+	TradeSymbolModuleOreRefineryI,
+	TradeSymbolModuleFuelRefineryI,
+	TradeSymbolMountGasSiphonI,
+	TradeSymbolMountGasSiphonIi,
+	TradeSymbolMountGasSiphonIii,
+	TradeSymbolMountSurveyorI,
+	TradeSymbolMountSurveyorIi,
+	TradeSymbolMountSurveyorIii,
+	TradeSymbolMountSensorArrayI,
+	TradeSymbolMountSensorArrayIi,
+	TradeSymbolMountSensorArrayIii,
+	TradeSymbolMountMiningLaserI,
+	TradeSymbolMountMiningLaserIi,
+	TradeSymbolMountMiningLaserIii,
+	TradeSymbolMountLaserCannonI,
+	TradeSymbolMountMissileLauncherI,
+	TradeSymbolMountTurretI,
+	TradeSymbolShipProbe,
+	TradeSymbolShipMiningDrone,
+	TradeSymbolShipSiphonDrone,
+	TradeSymbolShipInterceptor,
+	TradeSymbolShipLightHauler,
+	TradeSymbolShipCommandFrigate,
+	TradeSymbolShipExplorer,
+	TradeSymbolShipHeavyFreighter,
+	TradeSymbolShipLightShuttle,
+	TradeSymbolShipOreHound,
+	TradeSymbolShipRefiningFreighter,
+	TradeSymbolShipSurveyor,
+	TradeSymbolShipBulkFreighter,
+)
+
+// Valid is true if symbol is one the game is known to use.
+func (symbol TradeSymbol) Valid() bool {
+	return trade_symbols[symbol]
+}
+
+func (symbol *TradeSymbol) UnmarshalJSON(data []byte) error {
+	return unmarshal_enum(data, "trade symbol", symbol, trade_symbols)
+}
+
+// SupplyLevel is how much of a good a market has to sell.
+type SupplyLevel string
+
+const (
+	SupplyLevelScarce   SupplyLevel = "SCARCE"
+	SupplyLevelLimited  SupplyLevel = "LIMITED"
+	SupplyLevelModerate SupplyLevel = "MODERATE"
+	SupplyLevelHigh     SupplyLevel = "HIGH"
+	SupplyLevelAbundant SupplyLevel = "ABUNDANT"
+)
+
+var supply_levels = known_values(
+	SupplyLevelScarce,
+	SupplyLevelLimited,
+	SupplyLevelModerate,
+	SupplyLevelHigh,
+	SupplyLevelAbundant,
+)
+
+// Valid is true if level is one the game is known to use.
+func (level SupplyLevel) Valid() bool {
+	return supply_levels[level]
+}
+
+func (level *SupplyLevel) UnmarshalJSON(data []byte) error {
+	return unmarshal_enum(data, "supply level", level, supply_levels)
+}
+
+// ActivityLevel is how busy a market's trade in a good is, RESTRICTED when something is holding it back.
+type ActivityLevel string
+
+const (
+	ActivityLevelWeak       ActivityLevel = "WEAK"
+	ActivityLevelGrowing    ActivityLevel = "GROWING"
+	ActivityLevelStrong     ActivityLevel = "STRONG"
+	ActivityLevelRestricted ActivityLevel = "RESTRICTED"
+)
+
+var activity_levels = known_values(
+	ActivityLevelWeak,
+	ActivityLevelGrowing,
+	ActivityLevelStrong,
+	ActivityLevelRestricted,
+)
+
+// Valid is true if level is one the game is known to use.
+func (level ActivityLevel) Valid() bool {
+	return activity_levels[level]
+}
+
+func (level *ActivityLevel) UnmarshalJSON(data []byte) error {
+	return unmarshal_enum(data, "activity level", level, activity_levels)
+}
+
+// ShipType is a model of ship, what shipyards sell.
+type ShipType string
+
+const (
+	ShipTypeProbe             ShipType = "SHIP_PROBE"
+	ShipTypeMiningDrone       ShipType = "SHIP_MINING_DRONE"
+	ShipTypeSiphonDrone       ShipType = "SHIP_SIPHON_DRONE"
+	ShipTypeInterceptor       ShipType = "SHIP_INTERCEPTOR"
+	ShipTypeLightHauler       ShipType = "SHIP_LIGHT_HAULER"
+	ShipTypeCommandFrigate    ShipType = "SHIP_COMMAND_FRIGATE"
+	ShipTypeExplorer          ShipType = "SHIP_EXPLORER"
+	ShipTypeHeavyFreighter    ShipType = "SHIP_HEAVY_FREIGHTER"
+	ShipTypeLightShuttle      ShipType = "SHIP_LIGHT_SHUTTLE"
+	ShipTypeOreHound          ShipType = "SHIP_ORE_HOUND"
+	ShipTypeRefiningFreighter ShipType = "SHIP_REFINING_FREIGHTER"
+	ShipTypeSurveyor          ShipType = "SHIP_SURVEYOR"
+	ShipTypeBulkFreighter     ShipType = "SHIP_BULK_FREIGHTER"
+)
+
+var ship_types = known_values(
+	ShipTypeProbe,
+	ShipTypeMiningDrone,
+	ShipTypeSiphonDrone,
+	ShipTypeInterceptor,
+	ShipTypeLightHauler,
+	ShipTypeCommandFrigate,
+	ShipTypeExplorer,
+	ShipTypeHeavyFreighter,
+	ShipTypeLightShuttle,
+	ShipTypeOreHound,
+	ShipTypeRefiningFreighter,
+	ShipTypeSurveyor,
+	ShipTypeBulkFreighter,
+)
+
+// Valid is true if ship_type is one the game is known to use.
+func (ship_type ShipType) Valid() bool {
+	return ship_types[ship_type]
+}
+
+func (ship_type *ShipType) UnmarshalJSON(data []byte) error {
+	return unmarshal_enum(data, "ship type", ship_type, ship_types)
+}
+
+// MountSymbol is a mount a ship carries, such as a mining laser or a surveyor.
+type MountSymbol string
+
+const (
+	MountSymbolGasSiphonI       MountSymbol = "MOUNT_GAS_SIPHON_I"
+	MountSymbolGasSiphonIi      MountSymbol = "MOUNT_GAS_SIPHON_II"
+	MountSymbolGasSiphonIii     MountSymbol = "MOUNT_GAS_SIPHON_III"
+	MountSymbolSurveyorI        MountSymbol = "MOUNT_SURVEYOR_I"
+	MountSymbolSurveyorIi       MountSymbol = "MOUNT_SURVEYOR_II"
+	MountSymbolSurveyorIii      MountSymbol = "MOUNT_SURVEYOR_III"
+	MountSymbolSensorArrayI     MountSymbol = "MOUNT_SENSOR_ARRAY_I"
+	MountSymbolSensorArrayIi    MountSymbol = "MOUNT_SENSOR_ARRAY_II"
+	MountSymbolSensorArrayIii   MountSymbol = "MOUNT_SENSOR_ARRAY_III"
+	MountSymbolMiningLaserI     MountSymbol = "MOUNT_MINING_LASER_I"
+	MountSymbolMiningLaserIi    MountSymbol = "MOUNT_MINING_LASER_II"
+	MountSymbolMiningLaserIii   MountSymbol = "MOUNT_MINING_LASER_III"
+	MountSymbolLaserCannonI     MountSymbol = "MOUNT_LASER_CANNON_I"
+	MountSymbolMissileLauncherI MountSymbol = "MOUNT_MISSILE_LAUNCHER_I"
+	MountSymbolTurretI          MountSymbol = "MOUNT_TURRET_I"
+)
+
+var mount_symbols = known_values(
+	MountSymbolGasSiphonI,
+	MountSymbolGasSiphonIi,
+	MountSymbolGasSiphonIii,
+	MountSymbolSurveyorI,
+	MountSymbolSurveyorIi,
+	MountSymbolSurveyorIii,
+	MountSymbolSensorArrayI,
+	MountSymbolSensorArrayIi,
+	MountSymbolSensorArrayIii,
+	MountSymbolMiningLaserI,
+	MountSymbolMiningLaserIi,
+	MountSymbolMiningLaserIii,
+	MountSymbolLaserCannonI,
+	MountSymbolMissileLauncherI,
+	MountSymbolTurretI,
+)
+
+// Valid is true if mount is one the game is known to use.
+func (mount MountSymbol) Valid() bool {
+	return mount_symbols[mount]
+}
+
+func (mount *MountSymbol) UnmarshalJSON(data []byte) error {
+	return unmarshal_enum(data, "mount", mount, mount_symbols)
+}
+
+// ModuleSymbol is a module fitted inside a ship, such as a cargo hold or a refinery.
+type ModuleSymbol string
+
+const (
+	ModuleSymbolMineralProcessorI ModuleSymbol = "MODULE_MINERAL_PROCESSOR_I"
+	ModuleSymbolGasProcessorI     ModuleSymbol = "MODULE_GAS_PROCESSOR_I"
+	ModuleSymbolCargoHoldI        ModuleSymbol = "MODULE_CARGO_HOLD_I"
+	ModuleSymbolCargoHoldIi       ModuleSymbol = "MODULE_CARGO_HOLD_II"
+	ModuleSymbolCargoHoldIii      ModuleSymbol = "MODULE_CARGO_HOLD_III"
+	ModuleSymbolCrewQuartersI     ModuleSymbol = "MODULE_CREW_QUARTERS_I"
+	ModuleSymbolEnvoyQuartersI    ModuleSymbol = "MODULE_ENVOY_QUARTERS_I"
+	ModuleSymbolPassengerCabinI   ModuleSymbol = "MODULE_PASSENGER_CABIN_I"
+	ModuleSymbolMicroRefineryI    ModuleSymbol = "MODULE_MICRO_REFINERY_I"
+	ModuleSymbolOreRefineryI      ModuleSymbol = "MODULE_ORE_REFINERY_I"
+	ModuleSymbolFuelRefineryI     ModuleSymbol = "MODULE_FUEL_REFINERY_I"
+	ModuleSymbolScienceLabI       ModuleSymbol = "MODULE_SCIENCE_LAB_I"
+	ModuleSymbolJumpDriveI        ModuleSymbol = "MODULE_JUMP_DRIVE_I"
+	ModuleSymbolJumpDriveIi       ModuleSymbol = "MODULE_JUMP_DRIVE_II"
+	ModuleSymbolJumpDriveIii      ModuleSymbol = "MODULE_JUMP_DRIVE_III"
+	ModuleSymbolWarpDriveI        ModuleSymbol = "MODULE_WARP_DRIVE_I"
+	ModuleSymbolWarpDriveIi       ModuleSymbol = "MODULE_WARP_DRIVE_II"
+	ModuleSymbolWarpDriveIii      ModuleSymbol = "MODULE_WARP_DRIVE_III"
+	ModuleSymbolShieldGeneratorI  ModuleSymbol = "MODULE_SHIELD_GENERATOR_I"
+	ModuleSymbolShieldGeneratorIi ModuleSymbol = "MODULE_SHIELD_GENERATOR_II"
+)
+
+var module_symbols = known_values(
+	ModuleSymbolMineralProcessorI,
+	ModuleSymbolGasProcessorI,
+	ModuleSymbolCargoHoldI,
+	ModuleSymbolCargoHoldIi,
+	ModuleSymbolCargoHoldIii,
+	ModuleSymbolCrewQuartersI,
+	ModuleSymbolEnvoyQuartersI,
+	ModuleSymbolPassengerCabinI,
+	ModuleSymbolMicroRefineryI,
+	ModuleSymbolOreRefineryI,
+	ModuleSymbolFuelRefineryI,
+	ModuleSymbolScienceLabI,
+	ModuleSymbolJumpDriveI,
+	ModuleSymbolJumpDriveIi,
+	ModuleSymbolJumpDriveIii,
+	ModuleSymbolWarpDriveI,
+	ModuleSymbolWarpDriveIi,
+	ModuleSymbolWarpDriveIii,
+	ModuleSymbolShieldGeneratorI,
+	ModuleSymbolShieldGeneratorIi,
+)
+
+// Valid is true if module is one the game is known to use.
+func (module ModuleSymbol) Valid() bool {
+	return module_symbols[module]
+}
+
+func (module *ModuleSymbol) UnmarshalJSON(data []byte) error {
+	return unmarshal_enum(data, "module", module, module_symbols)
+}
+
+// TransactionType is which way the goods went in a transaction. The bot's ledger files its own kinds
+// of payment under other types, so unlike the types above these are not checked against the game's.
+type TransactionType string
+
+const (
+	TransactionTypePurchase TransactionType = "PURCHASE"
+	TransactionTypeSell     TransactionType = "SELL"
+)
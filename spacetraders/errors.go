@@ -0,0 +1,56 @@
+package spacetraders
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Error codes returned by the SpaceTraders API which the bot reacts to.
+// See https://docs.spacetraders.io/api-guide/response-errors
+const (
+	ErrorCodeCooldownConflict     = 4000
+	ErrorCodeShipNotEnoughFuel    = 4203
+	ErrorCodeShipInTransit        = 4214
+	ErrorCodeShipSurveyInvalid    = 4221
+	ErrorCodeShipSurveyExpired    = 4222
+	ErrorCodeShipSurveyExhausted  = 4225
+	ErrorCodeShipCargoFull        = 4228
+	ErrorCodeInsufficientCredits  = 4600
+	ErrorCodeMarketTradeUnitLimit = 4604
+)
+
+// APIError is returned when the game answers a request with an error object
+// instead of data. It carries the code, message and data fields of that object
+// along with the HTTP status of the response and, for 429s, how long the server asked us to wait.
+type APIError struct {
+	StatusCode int
+	Code       int64
+	Message    string
+	Data       interface{}
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("spacetraders: %d %s (http %d)", e.Code, e.Message, e.StatusCode)
+}
+
+// IsAPIErrorCode reports whether err is, or wraps, an APIError with the given code.
+func IsAPIErrorCode(err error, code int64) bool {
+	var api_error *APIError
+	if errors.As(err, &api_error) {
+		return api_error.Code == code
+	}
+	return false
+}
+
+// IsUnauthorized reports whether err is, or wraps, an APIError the game answered with 401. That is what
+// a token from before the last server reset gets.
+func IsUnauthorized(err error) bool {
+	var api_error *APIError
+	if errors.As(err, &api_error) {
+		return api_error.StatusCode == http.StatusUnauthorized
+	}
+	return false
+}
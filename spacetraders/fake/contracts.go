@@ -0,0 +1,176 @@
+package fake
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ianlshaw/go-spacetrading/spacetraders"
+)
+
+// contract_template is a delivery the faction may ask for, new contracts cycle through them.
+type contract_template struct {
+	trade_symbol       spacetraders.TradeSymbol
+	destination_symbol string
+	units              int64
+	on_accepted        int64
+	on_fulfilled       int64
+}
+
+var contract_templates = []contract_template{
+	{trade_symbol: "IRON", destination_symbol: "X1-FAKE-D4", units: 60, on_accepted: 5000, on_fulfilled: 20000},
+	{trade_symbol: "FOOD", destination_symbol: "X1-FAKE-B2", units: 80, on_accepted: 4000, on_fulfilled: 15000},
+	{trade_symbol: "ELECTRONICS", destination_symbol: "X1-FAKE-A1", units: 20, on_accepted: 8000, on_fulfilled: 30000},
+}
+
+func (server *Server) new_contract(agent *agent) *spacetraders.Contract {
+	template := contract_templates[len(agent.contracts)%len(contract_templates)]
+	now := server.Now()
+	contract := &spacetraders.Contract{
+		ID:            agent.agent.Symbol + "-contract-" + strconv.Itoa(len(agent.contracts)+1),
+		FactionSymbol: agent.agent.StartingFaction,
+		Type:          "PROCUREMENT",
+		Terms: spacetraders.Terms{
+			Deadline: api_time(now.Add(7 * 24 * time.Hour)),
+			Payment:  spacetraders.Payment{OnAccepted: template.on_accepted, OnFulfilled: template.on_fulfilled},
+			Deliver: []spacetraders.Deliver{{
+				TradeSymbol:       template.trade_symbol,
+				DestinationSymbol: template.destination_symbol,
+				UnitsRequired:     template.units,
+			}},
+		},
+		Expiration:       api_time(now.Add(24 * time.Hour)),
+		DeadlineToAccept: api_time(now.Add(24 * time.Hour)),
+	}
+	agent.contracts = append(agent.contracts, contract)
+	return contract
+}
+
+func (agent *agent) contract(id string) *spacetraders.Contract {
+	for _, contract := range agent.contracts {
+		if contract.ID == id {
+			return contract
+		}
+	}
+	return nil
+}
+
+// agent_contract finds the contract named in the path, writing a 404 if the agent does not have it.
+func agent_contract(writer http.ResponseWriter, request *http.Request, agent *agent) *spacetraders.Contract {
+	contract := agent.contract(request.PathValue("contract"))
+	if contract == nil {
+		write_error(writer, http.StatusNotFound, error_code_not_found, "Contract not found")
+	}
+	return contract
+}
+
+func (server *Server) handle_list_contracts(writer http.ResponseWriter, request *http.Request, agent *agent) {
+	start, end, meta := page_bounds(request, len(agent.contracts))
+	contracts := []spacetraders.Contract{}
+	for _, contract := range agent.contracts[start:end] {
+		contracts = append(contracts, *contract)
+	}
+	write_page(writer, contracts, meta)
+}
+
+func (server *Server) handle_get_contract(writer http.ResponseWriter, request *http.Request, agent *agent) {
+	if contract := agent_contract(writer, request, agent); contract != nil {
+		write_data(writer, http.StatusOK, contract)
+	}
+}
+
+func (server *Server) handle_accept_contract(writer http.ResponseWriter, request *http.Request, agent *agent) {
+	contract := agent_contract(writer, request, agent)
+	if contract == nil {
+		return
+	}
+	if contract.Accepted {
+		write_error(writer, http.StatusBadRequest, error_code_bad_request, "Contract has already been accepted")
+		return
+	}
+	contract.Accepted = true
+	agent.agent.Credits += contract.Terms.Payment.OnAccepted
+	write_data(writer, http.StatusOK, spacetraders.AcceptContractResponse{Agent: agent.agent, Contract: *contract})
+}
+
+func (server *Server) handle_deliver_contract(writer http.ResponseWriter, request *http.Request, agent *agent) {
+	contract := agent_contract(writer, request, agent)
+	if contract == nil {
+		return
+	}
+	payload := spacetraders.DeliverContractPayload{}
+	if err := json.NewDecoder(request.Body).Decode(&payload); err != nil || payload.Units < 1 {
+		write_error(writer, http.StatusBadRequest, error_code_bad_request, "Invalid deliver payload")
+		return
+	}
+	if !contract.Accepted || contract.Fulfilled {
+		write_error(writer, http.StatusBadRequest, error_code_bad_request, "Contract is not open for deliveries")
+		return
+	}
+	ship := agent.ship(payload.ShipSymbol)
+	if ship == nil {
+		write_error(writer, http.StatusNotFound, error_code_not_found, "Ship not found")
+		return
+	}
+	for i, deliver := range contract.Terms.Deliver {
+		if deliver.TradeSymbol != payload.TradeSymbol {
+			continue
+		}
+		if ship.Nav.Status != spacetraders.NavStatusDocked || ship.Nav.WaypointSymbol != deliver.DestinationSymbol {
+			write_error(writer, http.StatusBadRequest, error_code_bad_request, "Ship must be docked at "+deliver.DestinationSymbol)
+			return
+		}
+		if deliver.UnitsFulfilled+payload.Units > deliver.UnitsRequired {
+			write_error(writer, http.StatusBadRequest, error_code_bad_request, "Delivery exceeds the units required")
+			return
+		}
+		if !remove_cargo(ship, payload.TradeSymbol, payload.Units) {
+			write_error(writer, http.StatusBadRequest, error_code_bad_request, "Ship does not have enough "+string(payload.TradeSymbol))
+			return
+		}
+		contract.Terms.Deliver[i].UnitsFulfilled += payload.Units
+		write_data(writer, http.StatusOK, spacetraders.DeliverContractResponse{Contract: *contract, Cargo: ship.Cargo})
+		return
+	}
+	write_error(writer, http.StatusBadRequest, error_code_bad_request, "Contract does not require "+string(payload.TradeSymbol))
+}
+
+func (server *Server) handle_fulfill_contract(writer http.ResponseWriter, request *http.Request, agent *agent) {
+	contract := agent_contract(writer, request, agent)
+	if contract == nil {
+		return
+	}
+	if !contract.Accepted || contract.Fulfilled {
+		write_error(writer, http.StatusBadRequest, error_code_bad_request, "Contract is not open to be fulfilled")
+		return
+	}
+	for _, deliver := range contract.Terms.Deliver {
+		if deliver.UnitsFulfilled < deliver.UnitsRequired {
+			write_error(writer, http.StatusBadRequest, error_code_bad_request, "Contract deliveries are incomplete")
+			return
+		}
+	}
+	contract.Fulfilled = true
+	agent.agent.Credits += contract.Terms.Payment.OnFulfilled
+	write_data(writer, http.StatusOK, spacetraders.FulfillContractResponse{Agent: agent.agent, Contract: *contract})
+}
+
+func (server *Server) handle_negotiate_contract(writer http.ResponseWriter, request *http.Request, agent *agent) {
+	ship := agent_ship(writer, request, agent)
+	if ship == nil {
+		return
+	}
+	if ship.Nav.Status != spacetraders.NavStatusDocked || ship.Nav.WaypointSymbol != HeadquartersSymbol {
+		write_error(writer, http.StatusBadRequest, error_code_bad_request, "Ship must be docked at a faction waypoint")
+		return
+	}
+	for _, contract := range agent.contracts {
+		if !contract.Fulfilled {
+			write_error(writer, http.StatusBadRequest, error_code_bad_request, "Agent already has an open contract")
+			return
+		}
+	}
+	contract := server.new_contract(agent)
+	write_data(writer, http.StatusCreated, spacetraders.NegotiateContractResponse{Contract: *contract})
+}
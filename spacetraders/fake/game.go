@@ -0,0 +1,227 @@
+package fake
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/ianlshaw/go-spacetrading/spacetraders"
+)
+
+// how long it takes for half of a market's price pressure to wear off
+const pressure_half_life = 10 * time.Minute
+
+// how far a full trade volume moves a price
+const pressure_per_trade_volume = 0.02
+
+// api_time is t as the API would send it, to the millisecond.
+func api_time(t time.Time) spacetraders.Time {
+	return spacetraders.Time{Time: t.UTC().Truncate(time.Millisecond)}
+}
+
+// price_multiplier makes exports cheap and imports dear.
+func (good *market_good) price_multiplier() float64 {
+	switch good.trade_type {
+	case "EXPORT":
+		return 0.8
+	case "IMPORT":
+		return 1.2
+	}
+	return 1.0
+}
+
+func (good *market_good) purchase_price() int64 {
+	return int64(math.Round(float64(good.base_price) * good.price_multiplier() * (1 + good.pressure) * 1.05))
+}
+
+func (good *market_good) sell_price() int64 {
+	return int64(math.Round(float64(good.base_price) * good.price_multiplier() * (1 + good.pressure) * 0.95))
+}
+
+func (good *market_good) supply() spacetraders.SupplyLevel {
+	switch {
+	case good.pressure < -0.1:
+		return spacetraders.SupplyLevelAbundant
+	case good.pressure < 0:
+		return spacetraders.SupplyLevelHigh
+	case good.pressure < 0.1:
+		return spacetraders.SupplyLevelModerate
+	case good.pressure < 0.2:
+		return spacetraders.SupplyLevelLimited
+	}
+	return spacetraders.SupplyLevelScarce
+}
+
+func (good *market_good) trade_good() spacetraders.TradeGood {
+	return spacetraders.TradeGood{
+		Symbol:        good.symbol,
+		Type:          good.trade_type,
+		TradeVolume:   good.trade_volume,
+		Supply:        good.supply(),
+		Activity:      spacetraders.ActivityLevelWeak,
+		PurchasePrice: good.purchase_price(),
+		SellPrice:     good.sell_price(),
+	}
+}
+
+func (good *market_good) bought(units int64) {
+	good.pressure += pressure_per_trade_volume * float64(units) / float64(good.trade_volume)
+}
+
+func (good *market_good) sold(units int64) {
+	good.pressure -= pressure_per_trade_volume * float64(units) / float64(good.trade_volume)
+}
+
+// relax lets price pressure wear off for the time elapsed since the market was last touched.
+func (market *market) relax(now time.Time, last time.Time) {
+	elapsed := now.Sub(last)
+	if elapsed <= 0 {
+		return
+	}
+	decay := math.Pow(0.5, elapsed.Seconds()/pressure_half_life.Seconds())
+	for _, good := range market.goods {
+		good.pressure *= decay
+	}
+}
+
+func (market *market) good(symbol spacetraders.TradeSymbol) *market_good {
+	for _, good := range market.goods {
+		if good.symbol == symbol {
+			return good
+		}
+	}
+	return nil
+}
+
+func (market *market) market(show_prices bool) spacetraders.Market {
+	result := spacetraders.Market{
+		Symbol:       market.waypoint_symbol,
+		Exports:      []spacetraders.Exchange{},
+		Imports:      []spacetraders.Exchange{},
+		Exchange:     []spacetraders.Exchange{},
+		Transactions: []spacetraders.Transaction{},
+	}
+	for _, good := range market.goods {
+		exchange := spacetraders.Exchange{Symbol: good.symbol, Name: string(good.symbol)}
+		switch good.trade_type {
+		case "EXPORT":
+			result.Exports = append(result.Exports, exchange)
+		case "IMPORT":
+			result.Imports = append(result.Imports, exchange)
+		default:
+			result.Exchange = append(result.Exchange, exchange)
+		}
+		if show_prices {
+			result.TradeGoods = append(result.TradeGoods, good.trade_good())
+		}
+	}
+	return result
+}
+
+func new_ship(symbol string, ship_type spacetraders.ShipType, location spacetraders.Waypoint, faction string) *spacetraders.Ship {
+	template := ship_templates[ship_type]
+	mounts := []spacetraders.Mount{}
+	for _, mount := range template.mounts {
+		mounts = append(mounts, spacetraders.Mount{Symbol: mount, Name: string(mount), Strength: mount_strengths[mount]})
+	}
+	modules := []spacetraders.Module{}
+	for _, module := range template.modules {
+		modules = append(modules, spacetraders.Module{Symbol: module, Name: string(module)})
+	}
+	here := spacetraders.Destination{
+		Symbol:       location.Symbol,
+		Type:         location.Type,
+		SystemSymbol: location.SystemSymbol,
+		X:            location.X,
+		Y:            location.Y,
+	}
+	return &spacetraders.Ship{
+		Symbol: symbol,
+		Nav: spacetraders.Nav{
+			SystemSymbol:   location.SystemSymbol,
+			WaypointSymbol: location.Symbol,
+			Route:          spacetraders.Route{Origin: here, Destination: here},
+			Status:         spacetraders.NavStatusDocked,
+			FlightMode:     spacetraders.FlightModeCruise,
+		},
+		Fuel:     spacetraders.Fuel{Current: template.fuel_capacity, Capacity: template.fuel_capacity},
+		Cooldown: spacetraders.Cooldown{ShipSymbol: symbol},
+		Frame:    spacetraders.Frame{Symbol: template.frame, Name: template.frame, FuelCapacity: template.fuel_capacity},
+		Engine:   spacetraders.Engine{Symbol: "ENGINE_" + string(ship_type), Speed: template.speed},
+		Modules:  modules,
+		Mounts:   mounts,
+		Registration: spacetraders.Registration{
+			Name:          symbol,
+			FactionSymbol: faction,
+			Role:          template.role,
+		},
+		Cargo: spacetraders.Cargo{Capacity: template.cargo_capacity, Inventory: []spacetraders.InventoryItem{}},
+	}
+}
+
+// settle lands a ship which has reached its destination.
+func settle(ship *spacetraders.Ship, now time.Time) {
+	if ship.Nav.Status != spacetraders.NavStatusInTransit {
+		return
+	}
+	if !now.Before(ship.Nav.Route.Arrival.Time) {
+		ship.Nav.Status = spacetraders.NavStatusInOrbit
+		ship.Nav.WaypointSymbol = ship.Nav.Route.Destination.Symbol
+	}
+}
+
+// cool_down counts down the ship's cooldown, clearing it once it has expired.
+func cool_down(ship *spacetraders.Ship, now time.Time) {
+	if ship.Cooldown.Expiration.IsZero() {
+		return
+	}
+	expiration := ship.Cooldown.Expiration.Time
+	if !now.Before(expiration) {
+		ship.Cooldown = spacetraders.Cooldown{ShipSymbol: ship.Symbol}
+		return
+	}
+	ship.Cooldown.RemainingSeconds = int64(math.Ceil(expiration.Sub(now).Seconds()))
+}
+
+func start_cooldown(ship *spacetraders.Ship, now time.Time, seconds int64) {
+	ship.Cooldown = spacetraders.Cooldown{
+		ShipSymbol:       ship.Symbol,
+		TotalSeconds:     seconds,
+		RemainingSeconds: seconds,
+		Expiration:       api_time(now.Add(time.Duration(seconds) * time.Second)),
+	}
+}
+
+func add_cargo(ship *spacetraders.Ship, symbol spacetraders.TradeSymbol, units int64) {
+	ship.Cargo.Units += units
+	for i, item := range ship.Cargo.Inventory {
+		if item.Symbol == symbol {
+			ship.Cargo.Inventory[i].Units += units
+			return
+		}
+	}
+	ship.Cargo.Inventory = append(ship.Cargo.Inventory, spacetraders.InventoryItem{Symbol: symbol, Name: string(symbol), Units: units})
+}
+
+// remove_cargo takes units of symbol out of the hold, returning false if there are not enough.
+func remove_cargo(ship *spacetraders.Ship, symbol spacetraders.TradeSymbol, units int64) bool {
+	for i, item := range ship.Cargo.Inventory {
+		if item.Symbol != symbol {
+			continue
+		}
+		if item.Units < units {
+			return false
+		}
+		ship.Cargo.Units -= units
+		ship.Cargo.Inventory[i].Units -= units
+		if ship.Cargo.Inventory[i].Units == 0 {
+			ship.Cargo.Inventory = append(ship.Cargo.Inventory[:i], ship.Cargo.Inventory[i+1:]...)
+		}
+		return true
+	}
+	return false
+}
+
+func ship_symbol(callsign string, number int) string {
+	return callsign + "-" + strconv.FormatInt(int64(number), 16)
+}
@@ -0,0 +1,84 @@
+package fake
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ianlshaw/go-spacetrading/spacetraders"
+)
+
+const jump_cooldown = 60
+
+// what the antimatter for one jump costs
+const jump_price = 500
+
+func (server *Server) handle_jump_gate(writer http.ResponseWriter, request *http.Request, agent *agent) {
+	waypoint_symbol := request.PathValue("waypoint")
+	connections, ok := server.jump_gates[waypoint_symbol]
+	if !ok {
+		write_error(writer, http.StatusNotFound, error_code_not_found, "Jump gate not found")
+		return
+	}
+	write_data(writer, http.StatusOK, spacetraders.GetJumpGateResponse{Symbol: waypoint_symbol, Connections: connections})
+}
+
+func (server *Server) handle_jump(writer http.ResponseWriter, request *http.Request, agent *agent) {
+	ship := agent_ship(writer, request, agent)
+	if ship == nil {
+		return
+	}
+	payload := spacetraders.JumpShipPayload{}
+	if err := json.NewDecoder(request.Body).Decode(&payload); err != nil {
+		write_error(writer, http.StatusBadRequest, error_code_bad_request, "Invalid jump payload")
+		return
+	}
+	if ship.Nav.Status == spacetraders.NavStatusInTransit {
+		write_error(writer, http.StatusBadRequest, spacetraders.ErrorCodeShipInTransit, "Ship is currently in-transit")
+		return
+	}
+	if !ready_in_orbit(writer, ship) {
+		return
+	}
+	connections, ok := server.jump_gates[ship.Nav.WaypointSymbol]
+	if !ok {
+		write_error(writer, http.StatusBadRequest, error_code_bad_request, "Ship is not at a jump gate")
+		return
+	}
+	connected := false
+	for _, connection := range connections {
+		if connection == payload.WaypointSymbol {
+			connected = true
+		}
+	}
+	if !connected {
+		write_error(writer, http.StatusBadRequest, error_code_bad_request, "Jump gate does not connect to "+payload.WaypointSymbol)
+		return
+	}
+	if agent.agent.Credits < jump_price {
+		write_error(writer, http.StatusBadRequest, spacetraders.ErrorCodeInsufficientCredits, "Insufficient credits")
+		return
+	}
+
+	origin, _ := server.waypoint(ship.Nav.WaypointSymbol)
+	destination, _ := server.waypoint(payload.WaypointSymbol)
+	now := server.Now()
+	transaction := server.transaction(ship, "ANTIMATTER", spacetraders.TransactionTypePurchase, 1, jump_price)
+	agent.agent.Credits -= jump_price
+
+	ship.Nav.SystemSymbol = destination.SystemSymbol
+	ship.Nav.WaypointSymbol = destination.Symbol
+	ship.Nav.Route = spacetraders.Route{
+		Origin:        destination_of(origin),
+		Destination:   destination_of(destination),
+		DepartureTime: api_time(now),
+		Arrival:       api_time(now),
+	}
+	start_cooldown(ship, now, jump_cooldown)
+
+	write_data(writer, http.StatusOK, spacetraders.JumpShipResponse{
+		Nav:         ship.Nav,
+		Cooldown:    ship.Cooldown,
+		Transaction: transaction,
+		Agent:       agent.agent,
+	})
+}
@@ -0,0 +1,242 @@
+package fake
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/ianlshaw/go-spacetrading/spacetraders"
+)
+
+// cooldowns after each kind of ship action, in seconds
+const (
+	extract_cooldown = 70
+	survey_cooldown  = 60
+	refine_cooldown  = 60
+)
+
+const survey_lifetime = 15 * time.Minute
+
+// how many units can be pulled out of a survey's deposits before it is exhausted
+const survey_capacity = 100
+
+// a refinery turns this many units of ore into a third as much produce
+const refine_batch = 30
+
+type survey struct {
+	survey    spacetraders.Survey
+	remaining int64
+}
+
+// mount_strength adds up the strength of every mount on the ship which is one of mounts.
+func mount_strength(ship *spacetraders.Ship, mounts []spacetraders.MountSymbol) int64 {
+	var strength int64
+	for _, mount := range ship.Mounts {
+		if slices.Contains(mounts, mount.Symbol) {
+			strength += mount.Strength
+		}
+	}
+	return strength
+}
+
+func has_module(ship *spacetraders.Ship, modules []spacetraders.ModuleSymbol) bool {
+	for _, module := range ship.Modules {
+		if slices.Contains(modules, module.Symbol) {
+			return true
+		}
+	}
+	return false
+}
+
+// ready_in_orbit writes an error and returns false unless the ship is orbiting and off cooldown.
+func ready_in_orbit(writer http.ResponseWriter, ship *spacetraders.Ship) bool {
+	if ship.Nav.Status != spacetraders.NavStatusInOrbit {
+		write_error(writer, http.StatusBadRequest, error_code_bad_request, "Ship must be in orbit")
+		return false
+	}
+	if ship.Cooldown.RemainingSeconds > 0 {
+		write_error(writer, http.StatusConflict, spacetraders.ErrorCodeCooldownConflict, "Ship action is still on cooldown for "+strconv.FormatInt(ship.Cooldown.RemainingSeconds, 10)+" second(s)")
+		return false
+	}
+	return true
+}
+
+func (server *Server) handle_survey(writer http.ResponseWriter, request *http.Request, agent *agent) {
+	ship := agent_ship(writer, request, agent)
+	if ship == nil || !ready_in_orbit(writer, ship) {
+		return
+	}
+	count := mount_strength(ship, surveyors)
+	if count == 0 {
+		write_error(writer, http.StatusBadRequest, error_code_bad_request, "Ship does not have a surveyor mount")
+		return
+	}
+	found, ok := deposits[ship.Nav.WaypointSymbol]
+	if !ok {
+		write_error(writer, http.StatusBadRequest, error_code_bad_request, "Waypoint has nothing to survey")
+		return
+	}
+
+	now := server.Now()
+	surveys := []spacetraders.Survey{}
+	for i := int64(0); i < count; i++ {
+		server.survey_count++
+		number := server.survey_count
+		result := spacetraders.Survey{
+			Signature:  ship.Nav.WaypointSymbol + "-" + strconv.FormatInt(int64(number), 16),
+			Symbol:     ship.Nav.WaypointSymbol,
+			Deposits:   []spacetraders.SurveyDeposit{},
+			Expiration: api_time(now.Add(survey_lifetime)),
+			Size:       "SMALL",
+		}
+		// each survey leans towards a different part of the deposit
+		for j := 0; j < 3; j++ {
+			result.Deposits = append(result.Deposits, spacetraders.SurveyDeposit{Symbol: found[(number+j)%len(found)]})
+		}
+		server.surveys[result.Signature] = &survey{survey: result, remaining: survey_capacity}
+		surveys = append(surveys, result)
+	}
+	start_cooldown(ship, now, survey_cooldown)
+
+	write_data(writer, http.StatusCreated, spacetraders.CreateSurveyResponse{Cooldown: ship.Cooldown, Surveys: surveys})
+}
+
+func (server *Server) handle_extract(writer http.ResponseWriter, request *http.Request, agent *agent) {
+	ship := agent_ship(writer, request, agent)
+	if ship == nil || !ready_in_orbit(writer, ship) {
+		return
+	}
+	found, ok := deposits[ship.Nav.WaypointSymbol]
+	if !ok {
+		write_error(writer, http.StatusBadRequest, error_code_bad_request, "Waypoint has nothing to extract")
+		return
+	}
+	server.extract(writer, ship, found)
+}
+
+func (server *Server) handle_extract_with_survey(writer http.ResponseWriter, request *http.Request, agent *agent) {
+	ship := agent_ship(writer, request, agent)
+	if ship == nil {
+		return
+	}
+	payload := spacetraders.Survey{}
+	if err := json.NewDecoder(request.Body).Decode(&payload); err != nil {
+		write_error(writer, http.StatusBadRequest, error_code_bad_request, "Invalid survey payload")
+		return
+	}
+	if !ready_in_orbit(writer, ship) {
+		return
+	}
+	issued, ok := server.surveys[payload.Signature]
+	if !ok || issued.survey.Symbol != ship.Nav.WaypointSymbol {
+		write_error(writer, http.StatusBadRequest, spacetraders.ErrorCodeShipSurveyInvalid, "Survey "+payload.Signature+" is not valid for this waypoint")
+		return
+	}
+	if !server.Now().Before(issued.survey.Expiration.Time) {
+		delete(server.surveys, payload.Signature)
+		write_error(writer, http.StatusBadRequest, spacetraders.ErrorCodeShipSurveyExpired, "Survey "+payload.Signature+" has expired")
+		return
+	}
+	if issued.remaining <= 0 {
+		write_error(writer, http.StatusBadRequest, spacetraders.ErrorCodeShipSurveyExhausted, "Survey "+payload.Signature+" has been exhausted")
+		return
+	}
+	found := []spacetraders.TradeSymbol{}
+	for _, deposit := range issued.survey.Deposits {
+		found = append(found, deposit.Symbol)
+	}
+	if units := server.extract(writer, ship, found); units > 0 {
+		issued.remaining -= units
+	}
+}
+
+// extract mines one of found into the ship's hold and writes the response, returning the units extracted.
+// found is cycled through so the same sequence of calls always yields the same goods.
+func (server *Server) extract(writer http.ResponseWriter, ship *spacetraders.Ship, found []spacetraders.TradeSymbol) int64 {
+	strength := mount_strength(ship, mining_lasers)
+	if strength == 0 {
+		write_error(writer, http.StatusBadRequest, error_code_bad_request, "Ship does not have a mining mount")
+		return 0
+	}
+	space := ship.Cargo.Capacity - ship.Cargo.Units
+	if space <= 0 {
+		write_error(writer, http.StatusBadRequest, spacetraders.ErrorCodeShipCargoFull, "Ship cargo hold is full")
+		return 0
+	}
+	units := strength
+	if units > space {
+		units = space
+	}
+	symbol := found[server.extractions%len(found)]
+	server.extractions++
+
+	add_cargo(ship, symbol, units)
+	start_cooldown(ship, server.Now(), extract_cooldown)
+
+	write_data(writer, http.StatusCreated, spacetraders.ExtractResourcesResponse{
+		Cooldown: ship.Cooldown,
+		Extraction: spacetraders.Extraction{
+			ShipSymbol: ship.Symbol,
+			Yield:      spacetraders.Yield{Symbol: symbol, Units: units},
+		},
+		Cargo:  ship.Cargo,
+		Events: []spacetraders.Event{},
+	})
+	return units
+}
+
+func (server *Server) handle_refine(writer http.ResponseWriter, request *http.Request, agent *agent) {
+	ship := agent_ship(writer, request, agent)
+	if ship == nil {
+		return
+	}
+	payload := spacetraders.RefinePayload{}
+	if err := json.NewDecoder(request.Body).Decode(&payload); err != nil {
+		write_error(writer, http.StatusBadRequest, error_code_bad_request, "Invalid refine payload")
+		return
+	}
+	if !ready_in_orbit(writer, ship) {
+		return
+	}
+	if !has_module(ship, ore_refineries) {
+		write_error(writer, http.StatusBadRequest, error_code_bad_request, "Ship does not have a refinery module")
+		return
+	}
+	ore, ok := refined_from[payload.Produce]
+	if !ok {
+		write_error(writer, http.StatusBadRequest, error_code_bad_request, "Cannot refine "+string(payload.Produce))
+		return
+	}
+	if !remove_cargo(ship, ore, refine_batch) {
+		write_error(writer, http.StatusBadRequest, error_code_bad_request, "Refining "+string(payload.Produce)+" needs "+strconv.Itoa(refine_batch)+" "+string(ore))
+		return
+	}
+	add_cargo(ship, payload.Produce, refine_batch/3)
+	start_cooldown(ship, server.Now(), refine_cooldown)
+
+	write_data(writer, http.StatusCreated, spacetraders.RefineResponse{
+		Cargo:    ship.Cargo,
+		Cooldown: ship.Cooldown,
+		Produced: []spacetraders.RefineItem{{TradeSymbol: payload.Produce, Units: refine_batch / 3}},
+		Consumed: []spacetraders.RefineItem{{TradeSymbol: ore, Units: refine_batch}},
+	})
+}
+
+func (server *Server) handle_jettison(writer http.ResponseWriter, request *http.Request, agent *agent) {
+	ship := agent_ship(writer, request, agent)
+	if ship == nil {
+		return
+	}
+	payload := spacetraders.JettisonPayload{}
+	if err := json.NewDecoder(request.Body).Decode(&payload); err != nil || payload.Units < 1 {
+		write_error(writer, http.StatusBadRequest, error_code_bad_request, "Invalid jettison payload")
+		return
+	}
+	if !remove_cargo(ship, payload.Symbol, payload.Units) {
+		write_error(writer, http.StatusBadRequest, error_code_bad_request, "Ship does not have enough "+string(payload.Symbol))
+		return
+	}
+	write_data(writer, http.StatusOK, spacetraders.JettisonResponse{Cargo: ship.Cargo})
+}
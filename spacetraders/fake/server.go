@@ -0,0 +1,721 @@
+// Package fake is an in-process stand-in for the SpaceTraders API, so the bot can be
+// exercised without the network or spending real credits. It models a small home system
+// with a handful of markets and shipyards, travel time, fuel, and prices which move as
+// goods are bought and sold, plus two more systems reached through its jump gate.
+// Everything is deterministic given the server's clock.
+package fake
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ianlshaw/go-spacetrading/spacetraders"
+)
+
+// error codes the fake answers with, where the game's own are not modelled by the client
+const (
+	error_code_bad_request  = 400
+	error_code_unauthorized = 401
+	error_code_not_found    = 404
+)
+
+type agent struct {
+	agent     spacetraders.Agent
+	token     string
+	ships     []*spacetraders.Ship
+	contracts []*spacetraders.Contract
+}
+
+// Server is a fake SpaceTraders API listening on a local port.
+type Server struct {
+	// Now is the game clock, it can be replaced to move time along without waiting.
+	Now func() time.Time
+
+	// AccountToken, if set, has to be sent to register an agent, as the real game asks for.
+	AccountToken string
+
+	mu          sync.Mutex
+	http_server *httptest.Server
+	waypoints   []spacetraders.Waypoint
+	markets     map[string]*market
+	shipyards   map[string]*shipyard
+	jump_gates  map[string][]string
+	agents      map[string]*agent // by token
+	last_relax  time.Time
+
+	// the day the universe was last reset, and when the next reset is due
+	reset_date string
+	next_reset time.Time
+
+	// surveys handed out and not yet exhausted, by signature
+	surveys      map[string]*survey
+	survey_count int
+	extractions  int
+}
+
+// NewServer starts a fake server with a fresh universe. Close it when done.
+func NewServer() *Server {
+	server := &Server{Now: time.Now}
+	server.new_universe()
+	server.http_server = httptest.NewServer(server.Handler())
+	return server
+}
+
+// reset_frequency is how often the fake claims to be reset, it only actually is when Reset is called.
+const reset_frequency = 7 * 24 * time.Hour
+
+// Reset wipes the universe as the game does every week or so: every agent and its token is gone, and
+// markets start over.
+func (server *Server) Reset() {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	server.new_universe()
+}
+
+func (server *Server) new_universe() {
+	now := server.Now()
+	server.waypoints = default_waypoints()
+	server.markets = default_markets()
+	server.shipyards = default_shipyards()
+	server.jump_gates = default_jump_gates()
+	server.agents = make(map[string]*agent)
+	server.surveys = make(map[string]*survey)
+	server.survey_count = 0
+	server.extractions = 0
+	server.last_relax = now
+	server.reset_date = now.UTC().Format("2006-01-02")
+	server.next_reset = now.Add(reset_frequency)
+}
+
+// URL is the base url to give a spacetraders.Client.
+func (server *Server) URL() string {
+	return server.http_server.URL + "/v2/"
+}
+
+func (server *Server) Close() {
+	server.http_server.Close()
+}
+
+// Client returns a client pointed at the fake server. It is not rate limited.
+func (server *Server) Client(token string) *spacetraders.Client {
+	client := spacetraders.NewClient(token)
+	client.BaseURL = server.URL()
+	client.Limiter = nil
+	return client
+}
+
+// Handler serves the fake API, for mounting somewhere other than the built in listener.
+func (server *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v2/{$}", server.handle_status)
+	mux.HandleFunc("POST /v2/register", server.handle_register)
+	mux.HandleFunc("GET /v2/my/agent", server.authenticated(server.handle_agent))
+	mux.HandleFunc("GET /v2/my/ships", server.authenticated(server.handle_list_ships))
+	mux.HandleFunc("POST /v2/my/ships", server.authenticated(server.handle_purchase_ship))
+	mux.HandleFunc("POST /v2/my/ships/{$}", server.authenticated(server.handle_purchase_ship))
+	mux.HandleFunc("GET /v2/my/ships/{ship}", server.authenticated(server.handle_get_ship))
+	mux.HandleFunc("POST /v2/my/ships/{ship}/navigate", server.authenticated(server.handle_navigate))
+	mux.HandleFunc("PATCH /v2/my/ships/{ship}/nav", server.authenticated(server.handle_patch_nav))
+	mux.HandleFunc("POST /v2/my/ships/{ship}/jump", server.authenticated(server.handle_jump))
+	mux.HandleFunc("POST /v2/my/ships/{ship}/orbit", server.authenticated(server.handle_orbit))
+	mux.HandleFunc("POST /v2/my/ships/{ship}/dock", server.authenticated(server.handle_dock))
+	mux.HandleFunc("POST /v2/my/ships/{ship}/purchase", server.authenticated(server.handle_purchase_cargo))
+	mux.HandleFunc("POST /v2/my/ships/{ship}/sell", server.authenticated(server.handle_sell_cargo))
+	mux.HandleFunc("POST /v2/my/ships/{ship}/refuel", server.authenticated(server.handle_refuel))
+	mux.HandleFunc("POST /v2/my/ships/{ship}/survey", server.authenticated(server.handle_survey))
+	mux.HandleFunc("POST /v2/my/ships/{ship}/extract", server.authenticated(server.handle_extract))
+	mux.HandleFunc("POST /v2/my/ships/{ship}/extract/survey", server.authenticated(server.handle_extract_with_survey))
+	mux.HandleFunc("POST /v2/my/ships/{ship}/refine", server.authenticated(server.handle_refine))
+	mux.HandleFunc("POST /v2/my/ships/{ship}/jettison", server.authenticated(server.handle_jettison))
+	mux.HandleFunc("POST /v2/my/ships/{ship}/negotiate/contract", server.authenticated(server.handle_negotiate_contract))
+	mux.HandleFunc("GET /v2/my/contracts", server.authenticated(server.handle_list_contracts))
+	mux.HandleFunc("GET /v2/my/contracts/{contract}", server.authenticated(server.handle_get_contract))
+	mux.HandleFunc("POST /v2/my/contracts/{contract}/accept", server.authenticated(server.handle_accept_contract))
+	mux.HandleFunc("POST /v2/my/contracts/{contract}/deliver", server.authenticated(server.handle_deliver_contract))
+	mux.HandleFunc("POST /v2/my/contracts/{contract}/fulfill", server.authenticated(server.handle_fulfill_contract))
+	mux.HandleFunc("GET /v2/systems/{system}/waypoints", server.authenticated(server.handle_list_waypoints))
+	mux.HandleFunc("GET /v2/systems/{system}/waypoints/{waypoint}", server.authenticated(server.handle_get_waypoint))
+	mux.HandleFunc("GET /v2/systems/{system}/waypoints/{waypoint}/market", server.authenticated(server.handle_market))
+	mux.HandleFunc("GET /v2/systems/{system}/waypoints/{waypoint}/shipyard", server.authenticated(server.handle_shipyard))
+	mux.HandleFunc("GET /v2/systems/{system}/waypoints/{waypoint}/jump-gate", server.authenticated(server.handle_jump_gate))
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		// the game's clock rather than the machine's, the client corrects for the difference
+		writer.Header().Set("Date", server.Now().UTC().Format(http.TimeFormat))
+		mux.ServeHTTP(writer, request)
+	})
+}
+
+type agent_handler func(writer http.ResponseWriter, request *http.Request, agent *agent)
+
+// authenticated looks up the agent behind the bearer token and holds the server lock for the call.
+func (server *Server) authenticated(handler agent_handler) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		server.mu.Lock()
+		defer server.mu.Unlock()
+
+		token := strings.TrimPrefix(request.Header.Get("Authorization"), "Bearer ")
+		agent, ok := server.agents[token]
+		if !ok {
+			write_error(writer, http.StatusUnauthorized, error_code_unauthorized, "Missing or invalid token")
+			return
+		}
+		server.tick()
+		handler(writer, request, agent)
+	}
+}
+
+// tick brings the world up to date with the clock before a request is served.
+func (server *Server) tick() {
+	now := server.Now()
+	for _, market := range server.markets {
+		market.relax(now, server.last_relax)
+	}
+	server.last_relax = now
+	for _, agent := range server.agents {
+		for _, ship := range agent.ships {
+			settle(ship, now)
+			cool_down(ship, now)
+		}
+	}
+}
+
+func write_data(writer http.ResponseWriter, status int, data interface{}) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+	json.NewEncoder(writer).Encode(map[string]interface{}{"data": data})
+}
+
+func write_page(writer http.ResponseWriter, data interface{}, meta spacetraders.Meta) {
+	writer.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(writer).Encode(map[string]interface{}{"data": data, "meta": meta})
+}
+
+func write_error(writer http.ResponseWriter, status int, code int64, message string) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+	json.NewEncoder(writer).Encode(spacetraders.ErrorResponse{Error: spacetraders.Error{Code: code, Message: message}})
+}
+
+// page_bounds reads page and limit from the query string and returns the slice bounds for total items.
+func page_bounds(request *http.Request, total int) (start int, end int, meta spacetraders.Meta) {
+	page, err := strconv.Atoi(request.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit, err := strconv.Atoi(request.URL.Query().Get("limit"))
+	if err != nil || limit < 1 || limit > 20 {
+		limit = 10
+	}
+	start = (page - 1) * limit
+	if start > total {
+		start = total
+	}
+	end = start + limit
+	if end > total {
+		end = total
+	}
+	return start, end, spacetraders.Meta{Total: int64(total), Page: int64(page), Limit: int64(limit)}
+}
+
+func (server *Server) waypoint(symbol string) (spacetraders.Waypoint, bool) {
+	for _, waypoint := range server.waypoints {
+		if waypoint.Symbol == symbol {
+			return waypoint, true
+		}
+	}
+	return spacetraders.Waypoint{}, false
+}
+
+func (agent *agent) ship(symbol string) *spacetraders.Ship {
+	for _, ship := range agent.ships {
+		if ship.Symbol == symbol {
+			return ship
+		}
+	}
+	return nil
+}
+
+// agent_ship finds the ship named in the path, writing a 404 if the agent does not own it.
+func agent_ship(writer http.ResponseWriter, request *http.Request, agent *agent) *spacetraders.Ship {
+	ship := agent.ship(request.PathValue("ship"))
+	if ship == nil {
+		write_error(writer, http.StatusNotFound, error_code_not_found, "Ship not found")
+	}
+	return ship
+}
+
+func (server *Server) handle_status(writer http.ResponseWriter, request *http.Request) {
+	server.mu.Lock()
+	status := spacetraders.ServerStatus{
+		Status:    "SpaceTraders fake server is online",
+		Version:   "fake",
+		ResetDate: server.reset_date,
+		ServerResets: spacetraders.ServerResets{
+			Next:      api_time(server.next_reset),
+			Frequency: "weekly",
+		},
+	}
+	server.mu.Unlock()
+	writer.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(writer).Encode(status)
+}
+
+func (server *Server) handle_register(writer http.ResponseWriter, request *http.Request) {
+	payload := spacetraders.RegisterAgentPayload{}
+	if err := json.NewDecoder(request.Body).Decode(&payload); err != nil || payload.Symbol == "" {
+		write_error(writer, http.StatusBadRequest, error_code_bad_request, "Invalid registration payload")
+		return
+	}
+	if server.AccountToken != "" && request.Header.Get("Authorization") != "Bearer "+server.AccountToken {
+		write_error(writer, http.StatusUnauthorized, error_code_unauthorized, "Registering an agent needs an account token")
+		return
+	}
+	if err := spacetraders.ValidateCallsign(payload.Symbol); err != nil {
+		write_error(writer, http.StatusUnprocessableEntity, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	for _, existing := range server.agents {
+		if existing.agent.Symbol == payload.Symbol {
+			write_error(writer, http.StatusConflict, error_code_bad_request, "Agent symbol has already been claimed")
+			return
+		}
+	}
+
+	headquarters, _ := server.waypoint(HeadquartersSymbol)
+	new_agent := &agent{
+		token: "fake-token-" + payload.Symbol,
+		agent: spacetraders.Agent{
+			AccountID:       "fake-" + payload.Symbol,
+			Symbol:          payload.Symbol,
+			Headquarters:    HeadquartersSymbol,
+			Credits:         StartingCredits,
+			StartingFaction: payload.Faction,
+		},
+	}
+	new_agent.ships = append(new_agent.ships,
+		new_ship(ship_symbol(payload.Symbol, 1), spacetraders.ShipTypeCommandFrigate, headquarters, payload.Faction),
+		new_ship(ship_symbol(payload.Symbol, 2), spacetraders.ShipTypeProbe, headquarters, payload.Faction),
+	)
+	new_agent.agent.ShipCount = int64(len(new_agent.ships))
+	contract := server.new_contract(new_agent)
+	server.agents[new_agent.token] = new_agent
+
+	write_data(writer, http.StatusCreated, spacetraders.RegisterAgentResponse{
+		Agent:    new_agent.agent,
+		Contract: *contract,
+		Faction:  spacetraders.Faction{Symbol: payload.Faction},
+		Ship:     *new_agent.ships[0],
+		Token:    new_agent.token,
+	})
+}
+
+func (server *Server) handle_agent(writer http.ResponseWriter, request *http.Request, agent *agent) {
+	write_data(writer, http.StatusOK, agent.agent)
+}
+
+func (server *Server) handle_list_ships(writer http.ResponseWriter, request *http.Request, agent *agent) {
+	start, end, meta := page_bounds(request, len(agent.ships))
+	ships := []spacetraders.Ship{}
+	for _, ship := range agent.ships[start:end] {
+		ships = append(ships, *ship)
+	}
+	write_page(writer, ships, meta)
+}
+
+func (server *Server) handle_get_ship(writer http.ResponseWriter, request *http.Request, agent *agent) {
+	if ship := agent_ship(writer, request, agent); ship != nil {
+		write_data(writer, http.StatusOK, ship)
+	}
+}
+
+func (server *Server) handle_purchase_ship(writer http.ResponseWriter, request *http.Request, agent *agent) {
+	payload := spacetraders.PurchaseShipPayload{}
+	if err := json.NewDecoder(request.Body).Decode(&payload); err != nil {
+		write_error(writer, http.StatusBadRequest, error_code_bad_request, "Invalid purchase payload")
+		return
+	}
+	shipyard, ok := server.shipyards[payload.WaypointSymbol]
+	if !ok {
+		write_error(writer, http.StatusNotFound, error_code_not_found, "Shipyard not found")
+		return
+	}
+	sold_here := false
+	for _, ship_type := range shipyard.ship_types {
+		if ship_type == payload.ShipType {
+			sold_here = true
+		}
+	}
+	if !sold_here {
+		write_error(writer, http.StatusBadRequest, error_code_bad_request, "Shipyard does not sell "+string(payload.ShipType))
+		return
+	}
+	present := false
+	for _, ship := range agent.ships {
+		if ship.Nav.WaypointSymbol == payload.WaypointSymbol && ship.Nav.Status != spacetraders.NavStatusInTransit {
+			present = true
+		}
+	}
+	if !present {
+		write_error(writer, http.StatusBadRequest, error_code_bad_request, "No ship present at shipyard")
+		return
+	}
+	price := ship_templates[payload.ShipType].price
+	if agent.agent.Credits < price {
+		write_error(writer, http.StatusBadRequest, spacetraders.ErrorCodeInsufficientCredits, "Insufficient credits")
+		return
+	}
+
+	location, _ := server.waypoint(payload.WaypointSymbol)
+	ship := new_ship(ship_symbol(agent.agent.Symbol, len(agent.ships)+1), payload.ShipType, location, agent.agent.StartingFaction)
+	agent.ships = append(agent.ships, ship)
+	agent.agent.Credits -= price
+	agent.agent.ShipCount = int64(len(agent.ships))
+
+	write_data(writer, http.StatusCreated, spacetraders.PurchaseShipResponse{
+		Agent: agent.agent,
+		Ship:  *ship,
+		Transaction: spacetraders.Transaction{
+			WaypointSymbol: payload.WaypointSymbol,
+			ShipSymbol:     ship.Symbol,
+			ShipType:       payload.ShipType,
+			Price:          price,
+			Timestamp:      api_time(server.Now()),
+		},
+	})
+}
+
+func (server *Server) handle_navigate(writer http.ResponseWriter, request *http.Request, agent *agent) {
+	ship := agent_ship(writer, request, agent)
+	if ship == nil {
+		return
+	}
+	payload := spacetraders.NavigateShipPayload{}
+	if err := json.NewDecoder(request.Body).Decode(&payload); err != nil {
+		write_error(writer, http.StatusBadRequest, error_code_bad_request, "Invalid navigate payload")
+		return
+	}
+	switch ship.Nav.Status {
+	case spacetraders.NavStatusInTransit:
+		write_error(writer, http.StatusBadRequest, spacetraders.ErrorCodeShipInTransit, "Ship is currently in-transit")
+		return
+	case spacetraders.NavStatusDocked:
+		write_error(writer, http.StatusBadRequest, error_code_bad_request, "Ship must be in orbit to navigate")
+		return
+	}
+	if payload.WaypointSymbol == ship.Nav.WaypointSymbol {
+		write_error(writer, http.StatusBadRequest, error_code_bad_request, "Ship is already at the destination")
+		return
+	}
+	origin, _ := server.waypoint(ship.Nav.WaypointSymbol)
+	destination, ok := server.waypoint(payload.WaypointSymbol)
+	if !ok {
+		write_error(writer, http.StatusNotFound, error_code_not_found, "Waypoint not found")
+		return
+	}
+	if destination.SystemSymbol != origin.SystemSymbol {
+		write_error(writer, http.StatusBadRequest, error_code_bad_request, "Waypoint is in another system, ships must jump there")
+		return
+	}
+
+	distance := spacetraders.DistanceBetweenTwoWaypoints(origin, destination)
+	fuel := int64(0)
+	if ship.Fuel.Capacity > 0 {
+		fuel = spacetraders.FuelCost(distance, ship.Nav.FlightMode)
+		if fuel > ship.Fuel.Current {
+			write_error(writer, http.StatusBadRequest, spacetraders.ErrorCodeShipNotEnoughFuel, "Ship does not have enough fuel")
+			return
+		}
+	}
+	now := server.Now()
+	arrival := now.Add(spacetraders.TravelTime(distance, ship.Engine.Speed, ship.Nav.FlightMode))
+
+	ship.Fuel.Current -= fuel
+	ship.Fuel.Consumed = spacetraders.Consumed{Amount: fuel, Timestamp: api_time(now)}
+	ship.Nav.Status = spacetraders.NavStatusInTransit
+	ship.Nav.Route = spacetraders.Route{
+		Origin:        destination_of(origin),
+		Destination:   destination_of(destination),
+		DepartureTime: api_time(now),
+		Arrival:       api_time(arrival),
+	}
+
+	write_data(writer, http.StatusOK, spacetraders.NavigateShipResponse{Fuel: ship.Fuel, Nav: ship.Nav, Events: []spacetraders.Event{}})
+}
+
+func destination_of(waypoint spacetraders.Waypoint) spacetraders.Destination {
+	return spacetraders.Destination{
+		Symbol:       waypoint.Symbol,
+		Type:         waypoint.Type,
+		SystemSymbol: waypoint.SystemSymbol,
+		X:            waypoint.X,
+		Y:            waypoint.Y,
+	}
+}
+
+func (server *Server) handle_patch_nav(writer http.ResponseWriter, request *http.Request, agent *agent) {
+	ship := agent_ship(writer, request, agent)
+	if ship == nil {
+		return
+	}
+	payload := spacetraders.PatchShipNavPayload{}
+	if err := json.NewDecoder(request.Body).Decode(&payload); err != nil {
+		write_error(writer, http.StatusBadRequest, error_code_bad_request, "Invalid nav payload")
+		return
+	}
+	if !payload.FlightMode.Valid() {
+		write_error(writer, http.StatusBadRequest, error_code_bad_request, "Invalid flight mode "+string(payload.FlightMode))
+		return
+	}
+	ship.Nav.FlightMode = payload.FlightMode
+	write_data(writer, http.StatusOK, ship.Nav)
+}
+
+func (server *Server) handle_orbit(writer http.ResponseWriter, request *http.Request, agent *agent) {
+	ship := agent_ship(writer, request, agent)
+	if ship == nil {
+		return
+	}
+	if ship.Nav.Status == spacetraders.NavStatusInTransit {
+		write_error(writer, http.StatusBadRequest, spacetraders.ErrorCodeShipInTransit, "Ship is currently in-transit")
+		return
+	}
+	ship.Nav.Status = spacetraders.NavStatusInOrbit
+	write_data(writer, http.StatusOK, spacetraders.OrbitShipResponse{Nav: ship.Nav})
+}
+
+func (server *Server) handle_dock(writer http.ResponseWriter, request *http.Request, agent *agent) {
+	ship := agent_ship(writer, request, agent)
+	if ship == nil {
+		return
+	}
+	if ship.Nav.Status == spacetraders.NavStatusInTransit {
+		write_error(writer, http.StatusBadRequest, spacetraders.ErrorCodeShipInTransit, "Ship is currently in-transit")
+		return
+	}
+	ship.Nav.Status = spacetraders.NavStatusDocked
+	write_data(writer, http.StatusOK, spacetraders.DockShipResponse{Nav: ship.Nav})
+}
+
+// docked_market returns the market the ship is docked at, writing an error if there is none.
+func (server *Server) docked_market(writer http.ResponseWriter, ship *spacetraders.Ship) *market {
+	if ship.Nav.Status != spacetraders.NavStatusDocked {
+		write_error(writer, http.StatusBadRequest, error_code_bad_request, "Ship must be docked to trade")
+		return nil
+	}
+	market, ok := server.markets[ship.Nav.WaypointSymbol]
+	if !ok {
+		write_error(writer, http.StatusBadRequest, error_code_bad_request, "No market at this waypoint")
+		return nil
+	}
+	return market
+}
+
+func (server *Server) handle_purchase_cargo(writer http.ResponseWriter, request *http.Request, agent *agent) {
+	ship := agent_ship(writer, request, agent)
+	if ship == nil {
+		return
+	}
+	payload := spacetraders.PurchaseCargoPayload{}
+	if err := json.NewDecoder(request.Body).Decode(&payload); err != nil || payload.Units < 1 {
+		write_error(writer, http.StatusBadRequest, error_code_bad_request, "Invalid purchase payload")
+		return
+	}
+	market := server.docked_market(writer, ship)
+	if market == nil {
+		return
+	}
+	good := market.good(payload.Symbol)
+	if good == nil || good.trade_type == "IMPORT" {
+		write_error(writer, http.StatusBadRequest, error_code_bad_request, "Market does not sell "+string(payload.Symbol))
+		return
+	}
+	if payload.Units > good.trade_volume {
+		write_error(writer, http.StatusBadRequest, spacetraders.ErrorCodeMarketTradeUnitLimit, "Units exceed the trade volume of "+strconv.FormatInt(good.trade_volume, 10))
+		return
+	}
+	if ship.Cargo.Units+payload.Units > ship.Cargo.Capacity {
+		write_error(writer, http.StatusBadRequest, error_code_bad_request, "Ship cargo hold is full")
+		return
+	}
+	price := good.purchase_price()
+	total := price * payload.Units
+	if total > agent.agent.Credits {
+		write_error(writer, http.StatusBadRequest, spacetraders.ErrorCodeInsufficientCredits, "Insufficient credits")
+		return
+	}
+
+	agent.agent.Credits -= total
+	add_cargo(ship, payload.Symbol, payload.Units)
+	good.bought(payload.Units)
+
+	write_data(writer, http.StatusCreated, spacetraders.PurchaseCargoResponse{
+		Agent:       agent.agent,
+		Cargo:       ship.Cargo,
+		Transaction: server.transaction(ship, payload.Symbol, spacetraders.TransactionTypePurchase, payload.Units, price),
+	})
+}
+
+func (server *Server) handle_sell_cargo(writer http.ResponseWriter, request *http.Request, agent *agent) {
+	ship := agent_ship(writer, request, agent)
+	if ship == nil {
+		return
+	}
+	payload := spacetraders.SellCargoPayload{}
+	if err := json.NewDecoder(request.Body).Decode(&payload); err != nil || payload.Units < 1 {
+		write_error(writer, http.StatusBadRequest, error_code_bad_request, "Invalid sell payload")
+		return
+	}
+	market := server.docked_market(writer, ship)
+	if market == nil {
+		return
+	}
+	good := market.good(payload.Symbol)
+	if good == nil || good.trade_type == "EXPORT" {
+		write_error(writer, http.StatusBadRequest, error_code_bad_request, "Market does not buy "+string(payload.Symbol))
+		return
+	}
+	if payload.Units > good.trade_volume {
+		write_error(writer, http.StatusBadRequest, spacetraders.ErrorCodeMarketTradeUnitLimit, "Units exceed the trade volume of "+strconv.FormatInt(good.trade_volume, 10))
+		return
+	}
+	if !remove_cargo(ship, payload.Symbol, payload.Units) {
+		write_error(writer, http.StatusBadRequest, error_code_bad_request, "Ship does not have enough "+string(payload.Symbol))
+		return
+	}
+	price := good.sell_price()
+	agent.agent.Credits += price * payload.Units
+	good.sold(payload.Units)
+
+	write_data(writer, http.StatusCreated, spacetraders.SellCargoResponse{
+		Agent:       agent.agent,
+		Cargo:       ship.Cargo,
+		Transaction: server.transaction(ship, payload.Symbol, spacetraders.TransactionTypeSell, payload.Units, price),
+	})
+}
+
+func (server *Server) handle_refuel(writer http.ResponseWriter, request *http.Request, agent *agent) {
+	ship := agent_ship(writer, request, agent)
+	if ship == nil {
+		return
+	}
+	market := server.docked_market(writer, ship)
+	if market == nil {
+		return
+	}
+	good := market.good(spacetraders.TradeSymbolFuel)
+	if good == nil || good.trade_type == "IMPORT" {
+		write_error(writer, http.StatusBadRequest, error_code_bad_request, "Market does not sell FUEL")
+		return
+	}
+	units := ship.Fuel.Capacity - ship.Fuel.Current
+	// a unit of FUEL on the market fills 100 units of a ship's tank
+	market_units := int64(math.Ceil(float64(units) / 100))
+	price := good.purchase_price()
+	total := price * market_units
+	if total > agent.agent.Credits {
+		write_error(writer, http.StatusBadRequest, spacetraders.ErrorCodeInsufficientCredits, "Insufficient credits")
+		return
+	}
+	agent.agent.Credits -= total
+	ship.Fuel.Current = ship.Fuel.Capacity
+
+	transaction := server.transaction(ship, spacetraders.TradeSymbolFuel, spacetraders.TransactionTypePurchase, units, price)
+	transaction.TotalPrice = total
+	write_data(writer, http.StatusOK, spacetraders.RefuelShipResponse{Agent: agent.agent, Fuel: ship.Fuel, Transaction: transaction})
+}
+
+func (server *Server) transaction(ship *spacetraders.Ship, symbol spacetraders.TradeSymbol, trade_type spacetraders.TransactionType, units int64, price int64) spacetraders.Transaction {
+	return spacetraders.Transaction{
+		WaypointSymbol: ship.Nav.WaypointSymbol,
+		ShipSymbol:     ship.Symbol,
+		TradeSymbol:    symbol,
+		Type:           trade_type,
+		Units:          units,
+		PricePerUnit:   price,
+		TotalPrice:     price * units,
+		Timestamp:      api_time(server.Now()),
+	}
+}
+
+func has_trait(waypoint spacetraders.Waypoint, trait spacetraders.WaypointTrait) bool {
+	for _, each := range waypoint.Traits {
+		if each.Symbol == trait {
+			return true
+		}
+	}
+	return false
+}
+
+func (server *Server) handle_list_waypoints(writer http.ResponseWriter, request *http.Request, agent *agent) {
+	trait := spacetraders.WaypointTrait(request.URL.Query().Get("traits"))
+	waypoint_type := spacetraders.WaypointType(request.URL.Query().Get("type"))
+	matching := []spacetraders.Waypoint{}
+	for _, waypoint := range server.waypoints {
+		if waypoint.SystemSymbol != request.PathValue("system") {
+			continue
+		}
+		if trait != "" && !has_trait(waypoint, trait) {
+			continue
+		}
+		if waypoint_type != "" && waypoint.Type != waypoint_type {
+			continue
+		}
+		matching = append(matching, waypoint)
+	}
+	start, end, meta := page_bounds(request, len(matching))
+	write_page(writer, matching[start:end], meta)
+}
+
+func (server *Server) handle_get_waypoint(writer http.ResponseWriter, request *http.Request, agent *agent) {
+	waypoint, ok := server.waypoint(request.PathValue("waypoint"))
+	if !ok {
+		write_error(writer, http.StatusNotFound, error_code_not_found, "Waypoint not found")
+		return
+	}
+	write_data(writer, http.StatusOK, waypoint)
+}
+
+func (server *Server) handle_market(writer http.ResponseWriter, request *http.Request, agent *agent) {
+	waypoint_symbol := request.PathValue("waypoint")
+	market, ok := server.markets[waypoint_symbol]
+	if !ok {
+		write_error(writer, http.StatusNotFound, error_code_not_found, "Market not found")
+		return
+	}
+	// like the real game, prices are only visible with a ship present
+	show_prices := false
+	for _, ship := range agent.ships {
+		if ship.Nav.WaypointSymbol == waypoint_symbol && ship.Nav.Status != spacetraders.NavStatusInTransit {
+			show_prices = true
+		}
+	}
+	write_data(writer, http.StatusOK, market.market(show_prices))
+}
+
+func (server *Server) handle_shipyard(writer http.ResponseWriter, request *http.Request, agent *agent) {
+	shipyard, ok := server.shipyards[request.PathValue("waypoint")]
+	if !ok {
+		write_error(writer, http.StatusNotFound, error_code_not_found, "Shipyard not found")
+		return
+	}
+	result := spacetraders.Shipyard{
+		Symbol:       shipyard.waypoint_symbol,
+		ShipTypes:    []spacetraders.ShipyardShipType{},
+		Transactions: []spacetraders.Transaction{},
+		Ships:        []spacetraders.Ship{},
+	}
+	for _, ship_type := range shipyard.ship_types {
+		result.ShipTypes = append(result.ShipTypes, spacetraders.ShipyardShipType{Type: ship_type})
+	}
+	write_data(writer, http.StatusOK, result)
+}
@@ -0,0 +1,202 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ianlshaw/go-spacetrading/spacetraders"
+)
+
+const system_symbol = "X1-FAKE"
+
+// registered starts a server on a clock the test moves along and signs an agent up on it.
+func registered(t *testing.T, callsign string) (*Server, *spacetraders.Client, *time.Time) {
+	t.Helper()
+	server := NewServer()
+	t.Cleanup(server.Close)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	server.Now = func() time.Time { return now }
+	server.new_universe()
+
+	client := server.Client("")
+	registration, err := client.RegisterAgent(context.Background(), spacetraders.RegisterAgentPayload{Symbol: callsign, Faction: spacetraders.DefaultFaction})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.Token = registration.Token
+	return server, client, &now
+}
+
+func TestRegister(t *testing.T) {
+	tests := []struct {
+		name          string
+		account_token string
+		sent_token    string
+		callsign      string
+		status        int
+	}{
+		{"open registration", "", "", "TESTER", 0},
+		{"with the account token", "secret", "secret", "TESTER", 0},
+		{"without the account token", "secret", "", "TESTER", http.StatusUnauthorized},
+		{"with the wrong account token", "secret", "guess", "TESTER", http.StatusUnauthorized},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := NewServer()
+			defer server.Close()
+			server.AccountToken = test.account_token
+			client := server.Client("")
+			client.AccountToken = test.sent_token
+
+			registration, err := client.RegisterAgent(context.Background(), spacetraders.RegisterAgentPayload{Symbol: test.callsign})
+			if test.status == 0 {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if registration.Agent.Symbol != test.callsign || registration.Agent.Credits != StartingCredits {
+					t.Errorf("registered %+v", registration.Agent)
+				}
+				if registration.Token == "" {
+					t.Error("no token handed out")
+				}
+				return
+			}
+			var api_error *spacetraders.APIError
+			if !errors.As(err, &api_error) || api_error.StatusCode != test.status {
+				t.Errorf("got %v, want an APIError with status %d", err, test.status)
+			}
+		})
+	}
+}
+
+func TestRegisterTwice(t *testing.T) {
+	server, _, _ := registered(t, "TESTER")
+	_, err := server.Client("").RegisterAgent(context.Background(), spacetraders.RegisterAgentPayload{Symbol: "TESTER"})
+	var api_error *spacetraders.APIError
+	if !errors.As(err, &api_error) || api_error.StatusCode != http.StatusConflict {
+		t.Errorf("got %v, want the callsign to be taken", err)
+	}
+}
+
+func TestNavigate(t *testing.T) {
+	_, client, now := registered(t, "TESTER")
+	ctx := context.Background()
+	ship, err := client.GetShip(ctx, "TESTER-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ship.Nav.WaypointSymbol != HeadquartersSymbol {
+		t.Fatalf("starts at %s, want %s", ship.Nav.WaypointSymbol, HeadquartersSymbol)
+	}
+
+	if _, err := client.OrbitShip(ctx, ship.Symbol); err != nil {
+		t.Fatal(err)
+	}
+	navigation, err := client.NavigateShip(ctx, ship.Symbol, "X1-FAKE-B2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A1 is at 0,0 and B2 at 30,40
+	want_fuel := ship.Fuel.Current - spacetraders.FuelCost(50, ship.Nav.FlightMode)
+	if navigation.Fuel.Current != want_fuel {
+		t.Errorf("fuel %d after the flight, want %d", navigation.Fuel.Current, want_fuel)
+	}
+	want_arrival := now.Add(spacetraders.TravelTime(50, ship.Engine.Speed, ship.Nav.FlightMode))
+	if !navigation.Nav.Route.Arrival.Equal(want_arrival) {
+		t.Errorf("arrives %s, want %s", navigation.Nav.Route.Arrival, want_arrival)
+	}
+
+	*now = want_arrival.Add(-time.Second)
+	if ship, err = client.GetShip(ctx, ship.Symbol); err != nil {
+		t.Fatal(err)
+	}
+	if ship.Nav.Status != spacetraders.NavStatusInTransit {
+		t.Errorf("%s a second before arriving, want IN_TRANSIT", ship.Nav.Status)
+	}
+
+	*now = want_arrival
+	if ship, err = client.GetShip(ctx, ship.Symbol); err != nil {
+		t.Fatal(err)
+	}
+	if ship.Nav.Status != spacetraders.NavStatusInOrbit || ship.Nav.WaypointSymbol != "X1-FAKE-B2" {
+		t.Errorf("%s at %s once arrived, want IN_ORBIT at X1-FAKE-B2", ship.Nav.Status, ship.Nav.WaypointSymbol)
+	}
+}
+
+func TestPricesMoveWithTrade(t *testing.T) {
+	_, client, _ := registered(t, "TESTER")
+	ctx := context.Background()
+	if _, err := client.DockShip(ctx, "TESTER-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	price := func() int64 {
+		t.Helper()
+		market, err := client.GetMarket(ctx, system_symbol, HeadquartersSymbol)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, good := range market.TradeGoods {
+			if good.Symbol == spacetraders.TradeSymbolFood {
+				return good.PurchasePrice
+			}
+		}
+		t.Fatal("no FOOD for sale at " + HeadquartersSymbol)
+		return 0
+	}
+
+	before := price()
+	credits := int64(StartingCredits)
+	// a trade volume at a time, two of them move the price by more than rounding hides
+	for range 2 {
+		unit_price := price()
+		purchase, err := client.PurchaseCargo(ctx, "TESTER-1", spacetraders.TradeSymbolFood, 20)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if purchase.Transaction.TotalPrice != 20*unit_price {
+			t.Errorf("paid %d for 20 at %d each", purchase.Transaction.TotalPrice, unit_price)
+		}
+		credits -= purchase.Transaction.TotalPrice
+		if purchase.Agent.Credits != credits {
+			t.Errorf("left with %d credits, want %d", purchase.Agent.Credits, credits)
+		}
+	}
+	after := price()
+	if after <= before {
+		t.Errorf("FOOD costs %d after buying, %d before, want it dearer", after, before)
+	}
+
+	// FOOD is exported here, so the market will not take it back
+	_, err := client.SellCargo(ctx, "TESTER-1", spacetraders.TradeSymbolFood, 20)
+	var api_error *spacetraders.APIError
+	if !errors.As(err, &api_error) || api_error.StatusCode != http.StatusBadRequest {
+		t.Errorf("got %v selling FOOD where it is exported, want it refused", err)
+	}
+}
+
+func TestMarketPricesNeedAShip(t *testing.T) {
+	_, client, _ := registered(t, "TESTER")
+	market, err := client.GetMarket(context.Background(), system_symbol, "X1-FAKE-D4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(market.TradeGoods) != 0 {
+		t.Errorf("prices shown at X1-FAKE-D4 with no ship there: %+v", market.TradeGoods)
+	}
+}
+
+func TestResetTurnsTokensDown(t *testing.T) {
+	server, client, _ := registered(t, "TESTER")
+	ctx := context.Background()
+	if _, err := client.GetAgent(ctx); err != nil {
+		t.Fatal(err)
+	}
+	server.Reset()
+	if _, err := client.GetAgent(ctx); !spacetraders.IsUnauthorized(err) {
+		t.Errorf("got %v after a reset, want the token turned down", err)
+	}
+}
@@ -0,0 +1,154 @@
+package fake
+
+import (
+	"github.com/ianlshaw/go-spacetrading/spacetraders"
+)
+
+const SystemSymbol = "X1-FAKE"
+const HeadquartersSymbol = "X1-FAKE-A1"
+const StartingCredits = 175000
+
+// market_good is one line of a market's trade goods, its prices drift with pressure.
+type market_good struct {
+	symbol       spacetraders.TradeSymbol
+	trade_type   string // EXPORT, IMPORT or EXCHANGE
+	base_price   int64
+	trade_volume int64
+
+	// positive when the market has been bought from, negative when sold to
+	pressure float64
+}
+
+type market struct {
+	waypoint_symbol string
+	goods           []*market_good
+}
+
+type shipyard struct {
+	waypoint_symbol string
+	ship_types      []spacetraders.ShipType
+}
+
+// ship_template describes what a freshly purchased ship of a given type looks like.
+type ship_template struct {
+	role           spacetraders.ShipRole
+	frame          string
+	speed          int64
+	fuel_capacity  int64
+	cargo_capacity int64
+	price          int64
+	mounts         []spacetraders.MountSymbol
+	modules        []spacetraders.ModuleSymbol
+}
+
+var ship_templates = map[spacetraders.ShipType]ship_template{
+	spacetraders.ShipTypeCommandFrigate: {role: spacetraders.ShipRoleCommand, frame: "FRAME_FRIGATE", speed: 36, fuel_capacity: 400, cargo_capacity: 40, price: 0},
+	spacetraders.ShipTypeProbe:          {role: spacetraders.ShipRoleSatellite, frame: "FRAME_PROBE", speed: 9, fuel_capacity: 0, cargo_capacity: 0, price: 25000},
+	spacetraders.ShipTypeLightHauler:    {role: spacetraders.ShipRoleHauler, frame: "FRAME_LIGHT_FREIGHTER", speed: 30, fuel_capacity: 600, cargo_capacity: 80, price: 110000},
+	spacetraders.ShipTypeMiningDrone: {role: spacetraders.ShipRoleExcavator, frame: "FRAME_DRONE", speed: 10, fuel_capacity: 150, cargo_capacity: 15, price: 40000,
+		mounts: []spacetraders.MountSymbol{spacetraders.MountSymbolMiningLaserI}},
+	spacetraders.ShipTypeOreHound: {role: spacetraders.ShipRoleExcavator, frame: "FRAME_MINER", speed: 30, fuel_capacity: 500, cargo_capacity: 60, price: 160000,
+		mounts:  []spacetraders.MountSymbol{spacetraders.MountSymbolMiningLaserIi, spacetraders.MountSymbolSurveyorI},
+		modules: []spacetraders.ModuleSymbol{spacetraders.ModuleSymbolOreRefineryI}},
+}
+
+// mining mounts and how many units they pull out of a deposit per extraction, surveyors by how many surveys they make
+var mount_strengths = map[spacetraders.MountSymbol]int64{
+	spacetraders.MountSymbolMiningLaserI:  5,
+	spacetraders.MountSymbolMiningLaserIi: 10,
+	spacetraders.MountSymbolSurveyorI:     1,
+}
+
+var (
+	mining_lasers  = []spacetraders.MountSymbol{spacetraders.MountSymbolMiningLaserI, spacetraders.MountSymbolMiningLaserIi, spacetraders.MountSymbolMiningLaserIii}
+	surveyors      = []spacetraders.MountSymbol{spacetraders.MountSymbolSurveyorI, spacetraders.MountSymbolSurveyorIi, spacetraders.MountSymbolSurveyorIii}
+	ore_refineries = []spacetraders.ModuleSymbol{spacetraders.ModuleSymbolOreRefineryI}
+)
+
+// deposits is what can be extracted at each minable waypoint, repeated entries come up more often
+var deposits = map[string][]spacetraders.TradeSymbol{
+	"X1-FAKE-C3": {"IRON_ORE", "QUARTZ_SAND", "IRON_ORE", "ICE_WATER", "IRON_ORE"},
+}
+
+// refinery recipes, from ore to what it refines into
+var refined_from = map[spacetraders.TradeSymbol]spacetraders.TradeSymbol{
+	"IRON": "IRON_ORE",
+}
+
+func waypoint(symbol string, waypoint_type spacetraders.WaypointType, x int64, y int64, traits ...spacetraders.WaypointTrait) spacetraders.Waypoint {
+	result := spacetraders.Waypoint{
+		SystemSymbol: spacetraders.SystemSymbolOf(symbol),
+		Symbol:       symbol,
+		Type:         waypoint_type,
+		X:            x,
+		Y:            y,
+		Orbitals:     []spacetraders.Faction{},
+		Traits:       []spacetraders.Trait{},
+		Modifiers:    []interface{}{},
+		Faction:      spacetraders.Faction{Symbol: "COSMIC"},
+	}
+	for _, trait := range traits {
+		result.Traits = append(result.Traits, spacetraders.Trait{Symbol: trait, Name: string(trait)})
+	}
+	return result
+}
+
+// default_waypoints is the system every fake agent starts in, and two more strung out behind its jump gate.
+func default_waypoints() []spacetraders.Waypoint {
+	return []spacetraders.Waypoint{
+		waypoint("X1-FAKE-A1", "PLANET", 0, 0, "MARKETPLACE", "SHIPYARD"),
+		waypoint("X1-FAKE-B2", "MOON", 30, 40, "MARKETPLACE"),
+		waypoint("X1-FAKE-C3", "ENGINEERED_ASTEROID", -20, 10, "COMMON_METAL_DEPOSITS"),
+		waypoint("X1-FAKE-D4", "ORBITAL_STATION", 60, -20, "MARKETPLACE", "SHIPYARD"),
+		waypoint("X1-FAKE-E5", "JUMP_GATE", 100, 100),
+		waypoint("X1-FAR-A1", "JUMP_GATE", 0, 0),
+		waypoint("X1-FAR-B2", "PLANET", 25, -10, "MARKETPLACE"),
+		waypoint("X1-DEEP-A1", "JUMP_GATE", 0, 0),
+		waypoint("X1-DEEP-B2", "MOON", -15, 5, "MARKETPLACE"),
+	}
+}
+
+// default_jump_gates is where each jump gate leads
+func default_jump_gates() map[string][]string {
+	return map[string][]string{
+		"X1-FAKE-E5": {"X1-FAR-A1"},
+		"X1-FAR-A1":  {"X1-FAKE-E5", "X1-DEEP-A1"},
+		"X1-DEEP-A1": {"X1-FAR-A1"},
+	}
+}
+
+func default_markets() map[string]*market {
+	return map[string]*market{
+		"X1-FAKE-A1": {waypoint_symbol: "X1-FAKE-A1", goods: []*market_good{
+			{symbol: "FOOD", trade_type: "EXPORT", base_price: 40, trade_volume: 20},
+			{symbol: "ELECTRONICS", trade_type: "IMPORT", base_price: 250, trade_volume: 10},
+			{symbol: "FUEL", trade_type: "EXCHANGE", base_price: 70, trade_volume: 100},
+		}},
+		"X1-FAKE-B2": {waypoint_symbol: "X1-FAKE-B2", goods: []*market_good{
+			{symbol: "IRON", trade_type: "EXPORT", base_price: 60, trade_volume: 20},
+			{symbol: "FOOD", trade_type: "IMPORT", base_price: 40, trade_volume: 20},
+			{symbol: "IRON_ORE", trade_type: "IMPORT", base_price: 20, trade_volume: 40},
+			{symbol: "FUEL", trade_type: "EXCHANGE", base_price: 80, trade_volume: 100},
+		}},
+		"X1-FAKE-D4": {waypoint_symbol: "X1-FAKE-D4", goods: []*market_good{
+			{symbol: "ELECTRONICS", trade_type: "EXPORT", base_price: 250, trade_volume: 10},
+			{symbol: "FUEL", trade_type: "EXPORT", base_price: 60, trade_volume: 100},
+			{symbol: "IRON", trade_type: "IMPORT", base_price: 60, trade_volume: 20},
+		}},
+		"X1-FAR-B2": {waypoint_symbol: "X1-FAR-B2", goods: []*market_good{
+			{symbol: "FOOD", trade_type: "IMPORT", base_price: 60, trade_volume: 20},
+			{symbol: "FUEL", trade_type: "EXCHANGE", base_price: 75, trade_volume: 100},
+		}},
+		"X1-DEEP-B2": {waypoint_symbol: "X1-DEEP-B2", goods: []*market_good{
+			{symbol: "ELECTRONICS", trade_type: "IMPORT", base_price: 400, trade_volume: 10},
+			{symbol: "FUEL", trade_type: "EXCHANGE", base_price: 90, trade_volume: 100},
+		}},
+	}
+}
+
+func default_shipyards() map[string]*shipyard {
+	return map[string]*shipyard{
+		"X1-FAKE-A1": {waypoint_symbol: "X1-FAKE-A1", ship_types: []spacetraders.ShipType{spacetraders.ShipTypeProbe, spacetraders.ShipTypeLightHauler, spacetraders.ShipTypeMiningDrone}},
+		"X1-FAKE-D4": {waypoint_symbol: "X1-FAKE-D4", ship_types: []spacetraders.ShipType{spacetraders.ShipTypeProbe, spacetraders.ShipTypeOreHound}},
+	}
+}
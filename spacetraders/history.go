@@ -0,0 +1,240 @@
+package spacetraders
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultHistoryRetention is how long market snapshots are kept by default. The universe is reset
+// every week or two, so anything older describes markets which are gone.
+const DefaultHistoryRetention = 14 * 24 * time.Hour
+
+// MarketSnapshot is a market as GetMarket described it at a moment.
+type MarketSnapshot struct {
+	Timestamp time.Time `json:"timestamp"`
+	Market    Market    `json:"market"`
+}
+
+// PricePoint is what one TradeGood was going for at a market at a moment.
+type PricePoint struct {
+	Timestamp      time.Time
+	WaypointSymbol string
+	TradeGood      TradeGood
+}
+
+// MarketHistory keeps the market snapshots it is given, in memory and appended to a file one JSON
+// object per line, so prices survive a restart and can be looked back over. Only snapshots from the
+// retention window are kept, and a market which looks just as it did last time is not kept again, so
+// satellites watching a quiet market do not fill the file. It is safe for concurrent use.
+type MarketHistory struct {
+	mu        sync.Mutex
+	file      *os.File
+	retention time.Duration               // zero keeps everything
+	snapshots map[string][]MarketSnapshot // oldest first, by waypoint symbol
+}
+
+// OpenMarketHistory loads the history at filename, creating it if needed, and appends to it from then on.
+// Snapshots older than retention are dropped, zero keeps them all. When any are, or the file ends with a
+// line cut short by a crash part way through writing it, the file is rewritten without them first.
+func OpenMarketHistory(filename string, retention time.Duration) (*MarketHistory, error) {
+	history := &MarketHistory{retention: retention, snapshots: make(map[string][]MarketSnapshot)}
+	var cutoff time.Time
+	if retention > 0 {
+		cutoff = time.Now().Add(-retention)
+	}
+
+	rewrite := false
+	existing, err := os.Open(filename)
+	if err == nil {
+		reader := bufio.NewReader(existing)
+		line_number := 0
+		for {
+			line, err := reader.ReadBytes('\n')
+			if err == io.EOF {
+				// anything after the last newline is a record that was never finished, appending
+				// after it would spoil the next one too
+				if len(bytes.TrimSpace(line)) > 0 {
+					fmt.Printf("[ERROR] market history %s ends part way through a line, dropping it\n", filename)
+					rewrite = true
+				}
+				break
+			}
+			if err != nil {
+				existing.Close()
+				return nil, err
+			}
+			line_number++
+			if len(bytes.TrimSpace(line)) == 0 {
+				continue
+			}
+			snapshot := MarketSnapshot{}
+			if err := json.Unmarshal(line, &snapshot); err != nil {
+				fmt.Printf("[ERROR] market history %s line %d: %s\n", filename, line_number, err)
+				continue
+			}
+			if snapshot.Timestamp.Before(cutoff) {
+				rewrite = true
+				continue
+			}
+			history.add(snapshot)
+		}
+		existing.Close()
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	if rewrite {
+		if err := history.rewrite(filename); err != nil {
+			return nil, err
+		}
+	}
+	history.file, err = os.OpenFile(filename, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// rewrite replaces the file with the snapshots in memory. The new file is written alongside and renamed
+// over the old one, so a crash part way through leaves the old one.
+func (history *MarketHistory) rewrite(filename string) error {
+	temporary, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	// does nothing once the rename has happened
+	defer os.Remove(temporary.Name())
+
+	writer := bufio.NewWriter(temporary)
+	waypoint_symbols := make([]string, 0, len(history.snapshots))
+	for waypoint_symbol := range history.snapshots {
+		waypoint_symbols = append(waypoint_symbols, waypoint_symbol)
+	}
+	sort.Strings(waypoint_symbols)
+	for _, waypoint_symbol := range waypoint_symbols {
+		for _, snapshot := range history.snapshots[waypoint_symbol] {
+			line, err := json.Marshal(snapshot)
+			if err != nil {
+				temporary.Close()
+				return err
+			}
+			writer.Write(line)
+			writer.WriteByte('\n')
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		temporary.Close()
+		return err
+	}
+	if err := temporary.Sync(); err != nil {
+		temporary.Close()
+		return err
+	}
+	if err := temporary.Close(); err != nil {
+		return err
+	}
+	return os.Rename(temporary.Name(), filename)
+}
+
+func (history *MarketHistory) add(snapshot MarketSnapshot) {
+	waypoint_symbol := snapshot.Market.Symbol
+	snapshots := history.snapshots[waypoint_symbol]
+	snapshots = append(snapshots, snapshot)
+	// a snapshot can arrive out of order if clocks disagree, keep each waypoint sorted by time
+	if n := len(snapshots); n > 1 && snapshots[n-1].Timestamp.Before(snapshots[n-2].Timestamp) {
+		sort.SliceStable(snapshots, func(i, j int) bool { return snapshots[i].Timestamp.Before(snapshots[j].Timestamp) })
+	}
+	// forget what has fallen out of the retention window, the file sheds it next time it is opened
+	if history.retention > 0 {
+		cutoff := snapshots[len(snapshots)-1].Timestamp.Add(-history.retention)
+		expired := 0
+		for expired < len(snapshots) && snapshots[expired].Timestamp.Before(cutoff) {
+			expired++
+		}
+		if expired > 0 {
+			snapshots = append([]MarketSnapshot(nil), snapshots[expired:]...)
+		}
+	}
+	history.snapshots[waypoint_symbol] = snapshots
+}
+
+// Record stores a market as it was at timestamp, unless it looks just as it did in the last snapshot.
+func (history *MarketHistory) Record(market Market, timestamp time.Time) error {
+	snapshot := MarketSnapshot{Timestamp: timestamp.UTC(), Market: market}
+	line, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	history.mu.Lock()
+	defer history.mu.Unlock()
+	if snapshots := history.snapshots[market.Symbol]; len(snapshots) > 0 && reflect.DeepEqual(snapshots[len(snapshots)-1].Market, market) {
+		return nil
+	}
+	if _, err := history.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("market history: %w", err)
+	}
+	history.add(snapshot)
+	return nil
+}
+
+// Waypoints lists every market with at least one snapshot, sorted.
+func (history *MarketHistory) Waypoints() []string {
+	history.mu.Lock()
+	defer history.mu.Unlock()
+	waypoint_symbols := make([]string, 0, len(history.snapshots))
+	for waypoint_symbol := range history.snapshots {
+		waypoint_symbols = append(waypoint_symbols, waypoint_symbol)
+	}
+	sort.Strings(waypoint_symbols)
+	return waypoint_symbols
+}
+
+// Snapshots returns every snapshot of the market at waypoint_symbol, oldest first.
+func (history *MarketHistory) Snapshots(waypoint_symbol string) []MarketSnapshot {
+	history.mu.Lock()
+	defer history.mu.Unlock()
+	return append([]MarketSnapshot(nil), history.snapshots[waypoint_symbol]...)
+}
+
+// LatestPrices returns the most recent snapshot of the market at waypoint_symbol which has prices in it,
+// that is one taken while a ship was there.
+func (history *MarketHistory) LatestPrices(waypoint_symbol string) (MarketSnapshot, bool) {
+	history.mu.Lock()
+	defer history.mu.Unlock()
+	snapshots := history.snapshots[waypoint_symbol]
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		if len(snapshots[i].Market.TradeGoods) > 0 {
+			return snapshots[i], true
+		}
+	}
+	return MarketSnapshot{}, false
+}
+
+// Prices returns every price seen for trade_good_symbol at waypoint_symbol, oldest first.
+func (history *MarketHistory) Prices(waypoint_symbol string, trade_good_symbol TradeSymbol) []PricePoint {
+	history.mu.Lock()
+	defer history.mu.Unlock()
+	prices := []PricePoint{}
+	for _, snapshot := range history.snapshots[waypoint_symbol] {
+		for _, trade_good := range snapshot.Market.TradeGoods {
+			if trade_good.Symbol == trade_good_symbol {
+				prices = append(prices, PricePoint{Timestamp: snapshot.Timestamp, WaypointSymbol: waypoint_symbol, TradeGood: trade_good})
+			}
+		}
+	}
+	return prices
+}
+
+func (history *MarketHistory) Close() error {
+	return history.file.Close()
+}
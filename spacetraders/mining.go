@@ -0,0 +1,50 @@
+package spacetraders
+
+import "context"
+
+// CreateSurvey scans the waypoint the ship is orbiting for deposits. The ship needs a surveyor mount
+// and goes on cooldown afterwards.
+func (client *Client) CreateSurvey(ctx context.Context, ship_symbol string) (CreateSurveyResponse, error) {
+	endpoint := "my/ships/" + ship_symbol + "/survey"
+	payload := &EmptyPayload{}
+	data_container := CreateSurveyResponseData{}
+	err := client.decode_post(ctx, endpoint, payload, &data_container)
+	return data_container.Data, err
+}
+
+// ExtractResources mines the waypoint the ship is orbiting. The ship goes on cooldown afterwards.
+func (client *Client) ExtractResources(ctx context.Context, ship_symbol string) (ExtractResourcesResponse, error) {
+	endpoint := "my/ships/" + ship_symbol + "/extract"
+	payload := &EmptyPayload{}
+	data_container := ExtractResourcesResponseData{}
+	err := client.decode_post(ctx, endpoint, payload, &data_container)
+	return data_container.Data, err
+}
+
+// ExtractResourcesWithSurvey mines the deposits a survey found, which biases the yield towards them.
+func (client *Client) ExtractResourcesWithSurvey(ctx context.Context, ship_symbol string, survey Survey) (ExtractResourcesResponse, error) {
+	endpoint := "my/ships/" + ship_symbol + "/extract/survey"
+	data_container := ExtractResourcesResponseData{}
+	err := client.decode_post(ctx, endpoint, &survey, &data_container)
+	return data_container.Data, err
+}
+
+// RefineShip turns ore in the hold into produce, e.g. IRON_ORE into IRON. The ship needs a refinery module.
+func (client *Client) RefineShip(ctx context.Context, ship_symbol string, produce TradeSymbol) (RefineResponse, error) {
+	endpoint := "my/ships/" + ship_symbol + "/refine"
+	payload := &RefinePayload{}
+	payload.Produce = produce
+	data_container := RefineResponseData{}
+	err := client.decode_post(ctx, endpoint, payload, &data_container)
+	return data_container.Data, err
+}
+
+func (client *Client) Jettison(ctx context.Context, ship_symbol string, trade_good_symbol TradeSymbol, units int64) (JettisonResponse, error) {
+	endpoint := "my/ships/" + ship_symbol + "/jettison"
+	payload := &JettisonPayload{}
+	payload.Symbol = trade_good_symbol
+	payload.Units = units
+	data_container := JettisonResponseData{}
+	err := client.decode_post(ctx, endpoint, payload, &data_container)
+	return data_container.Data, err
+}
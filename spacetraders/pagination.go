@@ -0,0 +1,91 @@
+package spacetraders
+
+import (
+	"strconv"
+	"strings"
+)
+
+// the largest page size the API will return
+const page_limit = 20
+
+// Pager walks a paginated listing one item at a time, fetching the next page when the
+// current one runs out. Use it like a bufio.Scanner:
+//
+//	pager := client.ListWaypointsInSystem(ctx, system_symbol)
+//	for pager.Next() {
+//		waypoint := pager.Item()
+//	}
+//	if err := pager.Err(); err != nil {
+//		return err
+//	}
+type Pager[T any] struct {
+	fetch   func(page int64) ([]T, Meta, error)
+	page    int64
+	buffer  []T
+	current T
+	seen    int64
+	total   int64
+	done    bool
+	err     error
+}
+
+func NewPager[T any](fetch func(page int64) ([]T, Meta, error)) *Pager[T] {
+	return &Pager[T]{fetch: fetch, total: -1}
+}
+
+// Next advances to the next item, returning false when the listing is exhausted or a fetch failed.
+func (pager *Pager[T]) Next() bool {
+	for len(pager.buffer) == 0 {
+		if pager.done || pager.err != nil {
+			return false
+		}
+		if pager.total >= 0 && pager.seen >= pager.total {
+			pager.done = true
+			return false
+		}
+		pager.page++
+		items, meta, err := pager.fetch(pager.page)
+		if err != nil {
+			pager.err = err
+			return false
+		}
+		pager.total = meta.Total
+		pager.buffer = items
+		// an empty page means the listing shrank underneath us
+		if len(items) == 0 {
+			pager.done = true
+		}
+	}
+	pager.current = pager.buffer[0]
+	pager.buffer = pager.buffer[1:]
+	pager.seen++
+	return true
+}
+
+// Item returns the item Next advanced to.
+func (pager *Pager[T]) Item() T {
+	return pager.current
+}
+
+// Err returns the first error hit while fetching pages.
+func (pager *Pager[T]) Err() error {
+	return pager.err
+}
+
+// All drains the pager into a slice.
+func (pager *Pager[T]) All() ([]T, error) {
+	items := []T{}
+	for pager.Next() {
+		items = append(items, pager.Item())
+	}
+	return items, pager.Err()
+}
+
+// paged_endpoint adds the page and limit query parameters to endpoint.
+func paged_endpoint(endpoint string, page int64) string {
+	separator := "?"
+	if strings.Contains(endpoint, "?") {
+		separator = "&"
+	}
+	return endpoint + separator + "page=" + strconv.FormatInt(page, 10) + "&limit=" + strconv.Itoa(page_limit)
+}
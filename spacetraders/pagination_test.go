@@ -0,0 +1,90 @@
+package spacetraders
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// pages serves items page_size at a time, reporting total as the listing's size.
+func pages(items []int, page_size int, total int64) func(page int64) ([]int, Meta, error) {
+	return func(page int64) ([]int, Meta, error) {
+		start := min(int(page-1)*page_size, len(items))
+		end := min(start+page_size, len(items))
+		return items[start:end], Meta{Total: total, Page: page, Limit: int64(page_size)}, nil
+	}
+}
+
+func TestPager(t *testing.T) {
+	tests := []struct {
+		name      string
+		items     []int
+		page_size int
+		total     int64
+		want      []int
+		fetches   int64
+	}{
+		{"empty", []int{}, 20, 0, []int{}, 1},
+		{"one page", []int{1, 2, 3}, 20, 3, []int{1, 2, 3}, 1},
+		{"exactly full pages", []int{1, 2, 3, 4}, 2, 4, []int{1, 2, 3, 4}, 2},
+		{"last page part full", []int{1, 2, 3, 4, 5}, 2, 5, []int{1, 2, 3, 4, 5}, 3},
+		// the listing shrank after the first page said how big it was
+		{"shrank underneath", []int{1, 2, 3}, 2, 6, []int{1, 2, 3}, 3},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fetches := int64(0)
+			fetch := pages(test.items, test.page_size, test.total)
+			pager := NewPager(func(page int64) ([]int, Meta, error) {
+				fetches++
+				return fetch(page)
+			})
+			got, err := pager.All()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+			if fetches != test.fetches {
+				t.Errorf("fetched %d pages, want %d", fetches, test.fetches)
+			}
+		})
+	}
+}
+
+func TestPagerStopsOnError(t *testing.T) {
+	failure := errors.New("page 2 failed")
+	pager := NewPager(func(page int64) ([]int, Meta, error) {
+		if page == 2 {
+			return nil, Meta{}, failure
+		}
+		return []int{1, 2}, Meta{Total: 10, Page: page, Limit: 2}, nil
+	})
+	got, err := pager.All()
+	if !errors.Is(err, failure) {
+		t.Errorf("got error %v, want %v", err, failure)
+	}
+	if !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("got %v before the error, want [1 2]", got)
+	}
+	if pager.Next() {
+		t.Error("Next went on after a failed fetch")
+	}
+}
+
+func TestPagedEndpoint(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		page     int64
+		want     string
+	}{
+		{"my/ships", 1, "my/ships?page=1&limit=20"},
+		{"systems/X1/waypoints?traits=MARKETPLACE", 3, "systems/X1/waypoints?traits=MARKETPLACE&page=3&limit=20"},
+	}
+	for _, test := range tests {
+		if got := paged_endpoint(test.endpoint, test.page); got != test.want {
+			t.Errorf("paged_endpoint(%q, %d) = %q, want %q", test.endpoint, test.page, got, test.want)
+		}
+	}
+}
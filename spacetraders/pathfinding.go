@@ -0,0 +1,91 @@
+package spacetraders
+
+import "time"
+
+// PlanRoute finds the quickest way to fly a ship at CRUISE from where it is to another waypoint in the
+// same system. A leg longer than the fuel on board is split up with stops at fuel_stations, where the
+// tank gets filled, so a ship can reach anything within Fuel.Capacity of a station. If the ship starts
+// at a fuel station it is assumed to fill up there before leaving.
+//
+// The route is the waypoints to fly to in order, ending with the destination; every one before it is a
+// refuel stop. found is false if the ship would run dry whichever way it went.
+func PlanRoute(ship Ship, destination_symbol string, waypoints []Waypoint, fuel_stations map[string]bool) (route []string, found bool) {
+	origin_symbol := ship.Nav.WaypointSymbol
+	if origin_symbol == destination_symbol {
+		return []string{}, true
+	}
+
+	// the only places worth stopping are the fuel stations
+	by_symbol := make(map[string]Waypoint)
+	nodes := []string{}
+	for _, waypoint := range waypoints {
+		if _, seen := by_symbol[waypoint.Symbol]; seen {
+			continue
+		}
+		if waypoint.Symbol == origin_symbol || waypoint.Symbol == destination_symbol || fuel_stations[waypoint.Symbol] {
+			by_symbol[waypoint.Symbol] = waypoint
+			nodes = append(nodes, waypoint.Symbol)
+		}
+	}
+	if _, ok := by_symbol[origin_symbol]; !ok {
+		return nil, false
+	}
+	if _, ok := by_symbol[destination_symbol]; !ok {
+		return nil, false
+	}
+
+	// ships without a tank never need fuel
+	if ship.Fuel.Capacity == 0 {
+		return []string{destination_symbol}, true
+	}
+
+	fuel_on_leaving := func(symbol string) int64 {
+		if fuel_stations[symbol] {
+			return ship.Fuel.Capacity
+		}
+		return ship.Fuel.Current
+	}
+
+	// dijkstra over travel time, there are few enough stations that a linear scan for the next node will do
+	arrival := map[string]time.Duration{origin_symbol: 0}
+	came_from := make(map[string]string)
+	done := make(map[string]bool)
+	for {
+		current := ""
+		for _, symbol := range nodes {
+			if _, reached := arrival[symbol]; !reached || done[symbol] {
+				continue
+			}
+			if current == "" || arrival[symbol] < arrival[current] {
+				current = symbol
+			}
+		}
+		if current == "" {
+			return nil, false
+		}
+		if current == destination_symbol {
+			break
+		}
+		done[current] = true
+
+		for _, next := range nodes {
+			if done[next] {
+				continue
+			}
+			distance := DistanceBetweenTwoWaypoints(by_symbol[current], by_symbol[next])
+			if FuelCost(distance, FlightModeCruise) > fuel_on_leaving(current) {
+				continue
+			}
+			via_current := arrival[current] + TravelTime(distance, ship.Engine.Speed, FlightModeCruise)
+			if best, reached := arrival[next]; !reached || via_current < best {
+				arrival[next] = via_current
+				came_from[next] = current
+			}
+		}
+	}
+
+	for symbol := destination_symbol; symbol != origin_symbol; symbol = came_from[symbol] {
+		route = append([]string{symbol}, route...)
+	}
+	return route, true
+}
@@ -0,0 +1,52 @@
+package spacetraders
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPlanRoute(t *testing.T) {
+	// a line of waypoints 60 apart, with fuel sold at the two in the middle
+	waypoints := []Waypoint{
+		{Symbol: "X1-A", X: 0},
+		{Symbol: "X1-S1", X: 60},
+		{Symbol: "X1-S2", X: 120},
+		{Symbol: "X1-D", X: 180},
+		{Symbol: "X1-FAR", X: 1000},
+	}
+	fuel_stations := map[string]bool{"X1-S1": true, "X1-S2": true}
+	ship := func(at string, fuel int64, capacity int64) Ship {
+		ship := Ship{}
+		ship.Nav.WaypointSymbol = at
+		ship.Fuel.Current = fuel
+		ship.Fuel.Capacity = capacity
+		ship.Engine.Speed = 10
+		return ship
+	}
+
+	tests := []struct {
+		name        string
+		ship        Ship
+		destination string
+		want        []string
+		found       bool
+	}{
+		{"already there", ship("X1-A", 100, 100), "X1-A", []string{}, true},
+		{"within the tank", ship("X1-A", 100, 100), "X1-S1", []string{"X1-S1"}, true},
+		{"straight there beats stopping", ship("X1-A", 200, 200), "X1-D", []string{"X1-D"}, true},
+		{"a stop at each station", ship("X1-A", 100, 100), "X1-D", []string{"X1-S1", "X1-S2", "X1-D"}, true},
+		{"fills up where it starts", ship("X1-S1", 0, 100), "X1-S2", []string{"X1-S2"}, true},
+		{"cannot reach the first station", ship("X1-A", 30, 100), "X1-D", nil, false},
+		{"too far from any station", ship("X1-A", 100, 100), "X1-FAR", nil, false},
+		{"probes need no fuel", ship("X1-A", 0, 0), "X1-FAR", []string{"X1-FAR"}, true},
+		{"unknown destination", ship("X1-A", 100, 100), "X1-NOWHERE", nil, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			route, found := PlanRoute(test.ship, test.destination, waypoints, fuel_stations)
+			if found != test.found || !slices.Equal(route, test.want) {
+				t.Errorf("PlanRoute to %s = %v, %v, want %v, %v", test.destination, route, found, test.want, test.found)
+			}
+		})
+	}
+}
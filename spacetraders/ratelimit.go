@@ -0,0 +1,97 @@
+package spacetraders
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// The SpaceTraders API allows a steady 2 requests per second, plus a burst pool of
+// 30 requests which refills every 60 seconds.
+// See https://docs.spacetraders.io/api-guide/rate-limits
+const (
+	rate_limit_per_second  = 2
+	rate_limit_burst       = 30
+	rate_limit_burst_reset = 60 * time.Second
+)
+
+// RateLimiter is a token bucket shared by every call a Client makes.
+// Requests spend steady tokens first and fall back to the burst pool when those run out.
+type RateLimiter struct {
+	mu sync.Mutex
+
+	steady_tokens   float64
+	steady_capacity float64
+	steady_rate     float64 // tokens per second
+	last_refill     time.Time
+
+	burst_tokens   int
+	burst_capacity int
+	burst_reset    time.Duration
+	next_burst     time.Time
+}
+
+func NewRateLimiter(per_second float64, burst int, burst_reset time.Duration) *RateLimiter {
+	now := time.Now()
+	return &RateLimiter{
+		steady_tokens:   per_second,
+		steady_capacity: per_second,
+		steady_rate:     per_second,
+		last_refill:     now,
+		burst_tokens:    burst,
+		burst_capacity:  burst,
+		burst_reset:     burst_reset,
+		next_burst:      now.Add(burst_reset),
+	}
+}
+
+// NewServerRateLimiter returns a RateLimiter matching the limits the game enforces on each account.
+func NewServerRateLimiter() *RateLimiter {
+	return NewRateLimiter(rate_limit_per_second, rate_limit_burst, rate_limit_burst_reset)
+}
+
+// Wait blocks until a request may be sent, or ctx is done.
+func (limiter *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		delay := limiter.reserve()
+		if delay == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// reserve takes a token if one is available and returns 0, otherwise it returns how long
+// to wait before the next steady token arrives.
+func (limiter *RateLimiter) reserve() time.Duration {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	now := time.Now()
+	limiter.steady_tokens += now.Sub(limiter.last_refill).Seconds() * limiter.steady_rate
+	if limiter.steady_tokens > limiter.steady_capacity {
+		limiter.steady_tokens = limiter.steady_capacity
+	}
+	limiter.last_refill = now
+
+	if !now.Before(limiter.next_burst) {
+		limiter.burst_tokens = limiter.burst_capacity
+		limiter.next_burst = now.Add(limiter.burst_reset)
+	}
+
+	if limiter.steady_tokens >= 1 {
+		limiter.steady_tokens--
+		return 0
+	}
+	if limiter.burst_tokens > 0 {
+		limiter.burst_tokens--
+		return 0
+	}
+
+	missing := 1 - limiter.steady_tokens
+	return time.Duration(missing / limiter.steady_rate * float64(time.Second))
+}
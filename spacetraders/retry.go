@@ -0,0 +1,143 @@
+package spacetraders
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// POST endpoints which leave the game in the same state no matter how many times they land,
+// so they can be resent after a 5xx even though the first attempt may have been processed.
+var safe_post_suffixes = []string{"/orbit", "/dock"}
+
+// send_with_retry builds and sends a request, retrying it with exponential backoff and jitter when
+// the game is rate limiting us (429) or having trouble (5xx). A 429 is always retried because the
+// request was rejected before it was processed, everything else only when the request is safe to repeat.
+func (client *Client) send_with_retry(ctx context.Context, method string, request_url string, payload []byte) (response_body string, err error) {
+	repeatable := is_safe_to_repeat(method, request_url)
+	// a client built without NewClient still sends the request once
+	max_attempts := max(client.MaxAttempts, 1)
+
+	var last_err error
+	for attempt := 1; attempt <= max_attempts; attempt++ {
+		var request_body io.Reader
+		if payload != nil {
+			request_body = bytes.NewReader(payload)
+		}
+		response_body, err := client.attempt(ctx, method, request_url, request_body)
+		if err == nil {
+			return response_body, nil
+		}
+		if ctx.Err() != nil {
+			return response_body, err
+		}
+
+		delay, retryable := client.retry_delay(err, attempt, repeatable)
+		if !retryable {
+			return response_body, err
+		}
+		last_err = err
+		if attempt < max_attempts {
+			fmt.Printf("[INFO] retrying %s %s in %s (attempt %d of %d): %s\n", method, request_url, delay.Round(time.Millisecond), attempt, max_attempts, err)
+			select {
+			case <-ctx.Done():
+				return "", ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+	}
+	return "", fmt.Errorf("giving up after %d attempts: %w", max_attempts, last_err)
+}
+
+// attempt waits its turn with the rate limiter and sends the request once, bounded by RequestTimeout.
+// The timeout only starts once the request goes out, however long the queue for the limiter was.
+func (client *Client) attempt(ctx context.Context, method string, request_url string, request_body io.Reader) (string, error) {
+	if client.Limiter != nil {
+		if err := client.Limiter.Wait(ctx); err != nil {
+			return "", err
+		}
+	}
+	if client.RequestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, client.RequestTimeout)
+		defer cancel()
+	}
+	request, err := http.NewRequestWithContext(ctx, method, request_url, request_body)
+	if err != nil {
+		return "", err
+	}
+	return client.do_request(request)
+}
+
+func is_safe_to_repeat(method string, request_url string) bool {
+	// a PATCH sets a value, sending it twice sets it twice
+	if method == http.MethodGet || method == http.MethodPatch {
+		return true
+	}
+	for _, suffix := range safe_post_suffixes {
+		if strings.HasSuffix(request_url, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// retry_delay decides whether err is worth retrying and how long to wait first.
+func (client *Client) retry_delay(err error, attempt int, repeatable bool) (time.Duration, bool) {
+	var api_error *APIError
+	if errors.As(err, &api_error) {
+		switch {
+		case api_error.StatusCode == http.StatusTooManyRequests:
+		case api_error.StatusCode >= 500 && repeatable:
+		default:
+			return 0, false
+		}
+		if api_error.RetryAfter > 0 {
+			return api_error.RetryAfter, true
+		}
+		return client.backoff(attempt), true
+	}
+
+	// the connection failed before we got an answer
+	var url_error *url.Error
+	if errors.As(err, &url_error) && repeatable && !errors.Is(err, ErrCassetteMismatch) {
+		return client.backoff(attempt), true
+	}
+	return 0, false
+}
+
+// backoff returns a random delay of up to RetryBaseDelay * 2^(attempt-1), capped at RetryMaxDelay.
+func (client *Client) backoff(attempt int) time.Duration {
+	ceiling := client.RetryBaseDelay << (attempt - 1)
+	if ceiling <= 0 || ceiling > client.RetryMaxDelay {
+		ceiling = client.RetryMaxDelay
+	}
+	return ceiling/2 + time.Duration(rand.Int63n(int64(ceiling/2)+1))
+}
+
+// parse_retry_after reads how long the server asked us to wait from the Retry-After
+// header (seconds) or failing that x-ratelimit-reset (a timestamp).
+func parse_retry_after(header http.Header) time.Duration {
+	if retry_after := header.Get("Retry-After"); retry_after != "" {
+		if seconds, err := strconv.ParseFloat(retry_after, 64); err == nil {
+			return time.Duration(seconds * float64(time.Second))
+		}
+		if when, err := http.ParseTime(retry_after); err == nil {
+			return time.Until(when)
+		}
+	}
+	if reset := header.Get("x-ratelimit-reset"); reset != "" {
+		if when, err := time.Parse(time.RFC3339, reset); err == nil {
+			return time.Until(when)
+		}
+	}
+	return 0
+}
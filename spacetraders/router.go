@@ -0,0 +1,147 @@
+package spacetraders
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+)
+
+// SystemSymbolOf returns the system a waypoint is in, X1-DF55-20250Z is in X1-DF55.
+func SystemSymbolOf(waypoint_symbol string) string {
+	parts := strings.SplitN(waypoint_symbol, "-", 3)
+	if len(parts) < 3 {
+		return waypoint_symbol
+	}
+	return parts[0] + "-" + parts[1]
+}
+
+// SystemGraph is what has been charted of the jump gate network: the gate in each system and
+// the gates each of those connects to. It is safe for concurrent use.
+type SystemGraph struct {
+	mu          sync.Mutex
+	gates       map[string]string   // jump gate waypoint by system symbol
+	connections map[string][]string // connected jump gate waypoints by jump gate waypoint
+	explored    map[string]bool     // systems already looked at, whether or not they have a gate
+}
+
+func NewSystemGraph() *SystemGraph {
+	return &SystemGraph{
+		gates:       make(map[string]string),
+		connections: make(map[string][]string),
+		explored:    make(map[string]bool),
+	}
+}
+
+func (graph *SystemGraph) AddJumpGate(jump_gate GetJumpGateResponse) {
+	graph.mu.Lock()
+	defer graph.mu.Unlock()
+	graph.gates[SystemSymbolOf(jump_gate.Symbol)] = jump_gate.Symbol
+	graph.connections[jump_gate.Symbol] = jump_gate.Connections
+}
+
+// JumpGate returns the jump gate waypoint in system_symbol, if it has been charted.
+func (graph *SystemGraph) JumpGate(system_symbol string) (string, bool) {
+	graph.mu.Lock()
+	defer graph.mu.Unlock()
+	gate, ok := graph.gates[system_symbol]
+	return gate, ok
+}
+
+// Route returns the jump gates to pass through to get from one system to another with the fewest
+// jumps, starting with the gate in from_system and ending with the gate in to_system.
+// ok is false if no path is known yet.
+func (graph *SystemGraph) Route(from_system string, to_system string) (gates []string, ok bool) {
+	graph.mu.Lock()
+	defer graph.mu.Unlock()
+
+	start, ok := graph.gates[from_system]
+	if !ok {
+		return nil, false
+	}
+	if from_system == to_system {
+		return []string{start}, true
+	}
+
+	// breadth first, so the first time the destination system comes up is the shortest way there
+	came_from := map[string]string{start: ""}
+	queue := []string{start}
+	for len(queue) > 0 {
+		gate := queue[0]
+		queue = queue[1:]
+		if SystemSymbolOf(gate) == to_system {
+			for ; gate != ""; gate = came_from[gate] {
+				gates = append([]string{gate}, gates...)
+			}
+			return gates, true
+		}
+		for _, next := range graph.connections[gate] {
+			if _, seen := came_from[next]; seen {
+				continue
+			}
+			came_from[next] = gate
+			queue = append(queue, next)
+		}
+	}
+	return nil, false
+}
+
+// ExploreJumpGates charts the jump gate network out to max_jumps from system_symbol, adding what it
+// finds to graph. Systems already explored are not asked about again. Gates the game will not describe,
+// such as ones still under construction, are left out rather than treated as an error.
+func (client *Client) ExploreJumpGates(ctx context.Context, graph *SystemGraph, system_symbol string, max_jumps int) error {
+	systems := []string{system_symbol}
+	visited := map[string]bool{system_symbol: true}
+	for jumps := 0; jumps <= max_jumps && len(systems) > 0; jumps++ {
+		next_systems := []string{}
+		for _, system := range systems {
+			if err := client.explore_system(ctx, graph, system); err != nil {
+				return err
+			}
+			gate, ok := graph.JumpGate(system)
+			if !ok {
+				continue
+			}
+			graph.mu.Lock()
+			for _, connection := range graph.connections[gate] {
+				if !visited[SystemSymbolOf(connection)] {
+					visited[SystemSymbolOf(connection)] = true
+					next_systems = append(next_systems, SystemSymbolOf(connection))
+				}
+			}
+			graph.mu.Unlock()
+		}
+		systems = next_systems
+	}
+	return nil
+}
+
+func (client *Client) explore_system(ctx context.Context, graph *SystemGraph, system_symbol string) error {
+	graph.mu.Lock()
+	explored := graph.explored[system_symbol]
+	graph.mu.Unlock()
+	if explored {
+		return nil
+	}
+
+	gates, err := client.ListWaypointsInSystemByType(ctx, system_symbol, WaypointTypeJumpGate)
+	if err != nil {
+		return err
+	}
+	for _, gate := range gates {
+		jump_gate, err := client.GetJumpGate(ctx, system_symbol, gate.Symbol)
+		var api_error *APIError
+		if errors.As(err, &api_error) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		graph.AddJumpGate(jump_gate)
+	}
+
+	graph.mu.Lock()
+	graph.explored[system_symbol] = true
+	graph.mu.Unlock()
+	return nil
+}
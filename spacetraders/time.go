@@ -0,0 +1,92 @@
+package spacetraders
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// TimeFormat is how the API writes every timestamp.
+const TimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// Time is a timestamp as the API sends it. It writes itself back out in the same format, so a survey
+// handed back to the game is exactly what the game handed out. The zero Time is written as null, and
+// null or an empty string read as the zero Time, for timestamps the API leaves out.
+type Time struct {
+	time.Time
+}
+
+func (t Time) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(t.UTC().Format(TimeFormat))
+}
+
+func (t *Time) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*t = Time{}
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	if text == "" {
+		*t = Time{}
+		return nil
+	}
+	parsed, err := time.Parse(time.RFC3339Nano, text)
+	if err != nil {
+		return fmt.Errorf("timestamp %q: %w", text, err)
+	}
+	*t = Time{parsed}
+	return nil
+}
+
+// TimeUntilArrival is how long until the ship gets where it is going, 0 if it is not travelling or
+// should have arrived by now. now should be the game's time, see Client.Now.
+func (ship Ship) TimeUntilArrival(now time.Time) time.Duration {
+	if ship.Nav.Status != NavStatusInTransit || ship.Nav.Route.Arrival.IsZero() {
+		return 0
+	}
+	if until := ship.Nav.Route.Arrival.Sub(now); until > 0 {
+		return until
+	}
+	return 0
+}
+
+// Remaining is how long until the cooldown is over, 0 once it is. Without an expiration it can only
+// go on what RemainingSeconds was when the ship was fetched.
+func (cooldown Cooldown) Remaining(now time.Time) time.Duration {
+	if cooldown.Expiration.IsZero() {
+		return time.Duration(cooldown.RemainingSeconds) * time.Second
+	}
+	if remaining := cooldown.Expiration.Sub(now); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// Now is the time on the game's clock, going by the Date header of the last response. Arrivals and
+// cooldowns are in the game's time, so comparing them with this rather than the local clock means a
+// machine whose clock is off does not act too early or wait too long.
+func (client *Client) Now() time.Time {
+	return time.Now().Add(client.ClockSkew())
+}
+
+// ClockSkew is how far ahead of the local clock the game's clock is, negative if it is behind.
+func (client *Client) ClockSkew() time.Duration {
+	return time.Duration(client.skew.Load())
+}
+
+// observe_date updates the clock skew from a response's Date header, received is when it arrived.
+func (client *Client) observe_date(header http.Header, received time.Time) {
+	date, err := http.ParseTime(header.Get("Date"))
+	if err != nil {
+		return
+	}
+	// the header is cut down to the second, on average the game's clock was half a second further on
+	client.skew.Store(int64(date.Add(500 * time.Millisecond).Sub(received)))
+}
@@ -0,0 +1,50 @@
+package spacetraders
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestTimeJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want time.Time
+		out  string
+	}{
+		{"as the api sends it", `"2026-01-01T12:00:00.123Z"`, time.Date(2026, 1, 1, 12, 0, 0, 123e6, time.UTC), `"2026-01-01T12:00:00.123Z"`},
+		{"without fractions", `"2026-01-01T12:00:00Z"`, time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC), `"2026-01-01T12:00:00.000Z"`},
+		// written back out in UTC, as the api writes it
+		{"with an offset", `"2026-01-01T13:00:00.500+01:00"`, time.Date(2026, 1, 1, 12, 0, 0, 500e6, time.UTC), `"2026-01-01T12:00:00.500Z"`},
+		{"null", `null`, time.Time{}, `null`},
+		{"empty", `""`, time.Time{}, `null`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got Time
+			if err := json.Unmarshal([]byte(test.json), &got); err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(test.want) {
+				t.Errorf("read %s as %s, want %s", test.json, got.Time, test.want)
+			}
+			out, err := json.Marshal(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(out) != test.out {
+				t.Errorf("wrote %s back out as %s, want %s", test.json, out, test.out)
+			}
+		})
+	}
+}
+
+func TestTimeJSONRejectsGarbage(t *testing.T) {
+	for _, text := range []string{`"yesterday"`, `"2026-01-01"`, `12`} {
+		var got Time
+		if err := json.Unmarshal([]byte(text), &got); err == nil {
+			t.Errorf("read %s as %s, want an error", text, got.Time)
+		}
+	}
+}
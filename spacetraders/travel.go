@@ -0,0 +1,42 @@
+package spacetraders
+
+import (
+	"math"
+	"time"
+)
+
+// how much slower than the engine's speed each flight mode goes, a leg takes
+// round(distance) * multiplier / speed + 15 seconds
+var flight_mode_multipliers = map[FlightMode]float64{
+	FlightModeCruise:  25,
+	FlightModeBurn:    12.5,
+	FlightModeDrift:   250,
+	FlightModeStealth: 30,
+}
+
+// TravelTime is how long a leg of distance takes at the given engine speed and flight mode.
+// Unknown flight modes are treated as CRUISE.
+func TravelTime(distance float64, speed int64, flight_mode FlightMode) time.Duration {
+	multiplier, ok := flight_mode_multipliers[flight_mode]
+	if !ok {
+		multiplier = flight_mode_multipliers[FlightModeCruise]
+	}
+	if speed < 1 {
+		speed = 1
+	}
+	seconds := math.Round(math.Round(math.Max(1, distance))*(multiplier/float64(speed)) + 15)
+	return time.Duration(seconds) * time.Second
+}
+
+// FuelCost is how much fuel a leg of distance burns in the given flight mode.
+// DRIFT always costs 1 and BURN twice what CRUISE does.
+func FuelCost(distance float64, flight_mode FlightMode) int64 {
+	cruise := int64(math.Max(1, math.Round(distance)))
+	switch flight_mode {
+	case FlightModeDrift:
+		return 1
+	case FlightModeBurn:
+		return 2 * cruise
+	}
+	return cruise
+}
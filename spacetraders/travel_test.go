@@ -0,0 +1,52 @@
+package spacetraders
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTravelTime(t *testing.T) {
+	tests := []struct {
+		distance    float64
+		speed       int64
+		flight_mode FlightMode
+		want        time.Duration
+	}{
+		{100, 10, FlightModeCruise, 265 * time.Second},
+		{100, 10, FlightModeBurn, 140 * time.Second},
+		{100, 10, FlightModeDrift, 2515 * time.Second},
+		{100, 10, FlightModeStealth, 315 * time.Second},
+		// the distance is rounded before it is flown
+		{100.6, 10, FlightModeCruise, 268 * time.Second},
+		// a hop to an orbital still counts as 1
+		{0, 10, FlightModeCruise, 18 * time.Second},
+		{100, 0, FlightModeCruise, 2515 * time.Second},
+		{100, 10, FlightMode("WARP"), 265 * time.Second},
+	}
+	for _, test := range tests {
+		if got := TravelTime(test.distance, test.speed, test.flight_mode); got != test.want {
+			t.Errorf("TravelTime(%v, %d, %s) = %s, want %s", test.distance, test.speed, test.flight_mode, got, test.want)
+		}
+	}
+}
+
+func TestFuelCost(t *testing.T) {
+	tests := []struct {
+		distance    float64
+		flight_mode FlightMode
+		want        int64
+	}{
+		{100, FlightModeCruise, 100},
+		{100, FlightModeBurn, 200},
+		{100, FlightModeDrift, 1},
+		{100, FlightModeStealth, 100},
+		{99.5, FlightModeCruise, 100},
+		{0.2, FlightModeCruise, 1},
+		{0.2, FlightModeBurn, 2},
+	}
+	for _, test := range tests {
+		if got := FuelCost(test.distance, test.flight_mode); got != test.want {
+			t.Errorf("FuelCost(%v, %s) = %d, want %d", test.distance, test.flight_mode, got, test.want)
+		}
+	}
+}
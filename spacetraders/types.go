@@ -1,4 +1,4 @@
-package main
+package spacetraders
 
 type ErrorResponse struct {
 	Error Error `json:"error"`
@@ -15,6 +15,10 @@ type ListShipsResponseData struct {
 	Meta Meta   `json:"meta"`
 }
 
+type GetShipResponseData struct {
+	Data Ship `json:"data"`
+}
+
 type Ship struct {
 	Symbol       string       `json:"symbol"`
 	Nav          Nav          `json:"nav"`
@@ -40,6 +44,7 @@ type Cooldown struct {
 	ShipSymbol       string `json:"shipSymbol"`
 	TotalSeconds     int64  `json:"totalSeconds"`
 	RemainingSeconds int64  `json:"remainingSeconds"`
+	Expiration       Time   `json:"expiration"`
 }
 
 type Crew struct {
@@ -85,12 +90,12 @@ type Fuel struct {
 }
 
 type Consumed struct {
-	Amount    int64  `json:"amount"`
-	Timestamp string `json:"timestamp"`
+	Amount    int64 `json:"amount"`
+	Timestamp Time  `json:"timestamp"`
 }
 
 type Module struct {
-	Symbol       string             `json:"symbol"`
+	Symbol       ModuleSymbol       `json:"symbol"`
 	Name         string             `json:"name"`
 	Description  string             `json:"description"`
 	Capacity     *int64             `json:"capacity,omitempty"`
@@ -104,7 +109,7 @@ type ModuleRequirements struct {
 }
 
 type Mount struct {
-	Symbol       string             `json:"symbol"`
+	Symbol       MountSymbol        `json:"symbol"`
 	Name         string             `json:"name"`
 	Description  string             `json:"description"`
 	Strength     int64              `json:"strength"`
@@ -113,26 +118,26 @@ type Mount struct {
 }
 
 type Nav struct {
-	SystemSymbol   string `json:"systemSymbol"`
-	WaypointSymbol string `json:"waypointSymbol"`
-	Route          Route  `json:"route"`
-	Status         string `json:"status"`
-	FlightMode     string `json:"flightMode"`
+	SystemSymbol   string     `json:"systemSymbol"`
+	WaypointSymbol string     `json:"waypointSymbol"`
+	Route          Route      `json:"route"`
+	Status         NavStatus  `json:"status"`
+	FlightMode     FlightMode `json:"flightMode"`
 }
 
 type Route struct {
 	Origin        Destination `json:"origin"`
 	Destination   Destination `json:"destination"`
-	Arrival       string      `json:"arrival"`
-	DepartureTime string      `json:"departureTime"`
+	Arrival       Time        `json:"arrival"`
+	DepartureTime Time        `json:"departureTime"`
 }
 
 type Destination struct {
-	Symbol       string `json:"symbol"`
-	Type         string `json:"type"`
-	SystemSymbol string `json:"systemSymbol"`
-	X            int64  `json:"x"`
-	Y            int64  `json:"y"`
+	Symbol       string       `json:"symbol"`
+	Type         WaypointType `json:"type"`
+	SystemSymbol string       `json:"systemSymbol"`
+	X            int64        `json:"x"`
+	Y            int64        `json:"y"`
 }
 
 type Reactor struct {
@@ -150,9 +155,9 @@ type ReactorRequirements struct {
 }
 
 type Registration struct {
-	Name          string `json:"name"`
-	FactionSymbol string `json:"factionSymbol"`
-	Role          string `json:"role"`
+	Name          string   `json:"name"`
+	FactionSymbol string   `json:"factionSymbol"`
+	Role          ShipRole `json:"role"`
 }
 
 type Meta struct {
@@ -169,7 +174,7 @@ type ListWaypointsInSystemResponseData struct {
 type Waypoint struct {
 	SystemSymbol        string        `json:"systemSymbol"`
 	Symbol              string        `json:"symbol"`
-	Type                string        `json:"type"`
+	Type                WaypointType  `json:"type"`
 	X                   int64         `json:"x"`
 	Y                   int64         `json:"y"`
 	Orbitals            []Faction     `json:"orbitals"`
@@ -182,7 +187,7 @@ type Waypoint struct {
 
 type Chart struct {
 	SubmittedBy string `json:"submittedBy"`
-	SubmittedOn string `json:"submittedOn"`
+	SubmittedOn Time   `json:"submittedOn"`
 }
 
 type Faction struct {
@@ -190,9 +195,9 @@ type Faction struct {
 }
 
 type Trait struct {
-	Symbol      string `json:"symbol"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
+	Symbol      WaypointTrait `json:"symbol"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
 }
 
 type GetMarketResponseData struct {
@@ -209,30 +214,34 @@ type Market struct {
 }
 
 type Exchange struct {
-	Symbol      string `json:"symbol"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
+	Symbol      TradeSymbol `json:"symbol"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
 }
 
 type TradeGood struct {
-	Symbol        string `json:"symbol"`
-	Type          string `json:"type"`
-	TradeVolume   int64  `json:"tradeVolume"`
-	Supply        string `json:"supply"`
-	Activity      string `json:"activity"`
-	PurchasePrice int64  `json:"purchasePrice"`
-	SellPrice     int64  `json:"sellPrice"`
+	Symbol        TradeSymbol   `json:"symbol"`
+	Type          string        `json:"type"`
+	TradeVolume   int64         `json:"tradeVolume"`
+	Supply        SupplyLevel   `json:"supply"`
+	Activity      ActivityLevel `json:"activity"`
+	PurchasePrice int64         `json:"purchasePrice"`
+	SellPrice     int64         `json:"sellPrice"`
 }
 
 type Transaction struct {
-	WaypointSymbol string `json:"waypointSymbol"`
-	ShipSymbol     string `json:"shipSymbol"`
-	TradeSymbol    string `json:"tradeSymbol"`
-	Type           string `json:"type"`
-	Units          int64  `json:"units"`
-	PricePerUnit   int64  `json:"pricePerUnit"`
-	TotalPrice     int64  `json:"totalPrice"`
-	Timestamp      string `json:"timestamp"`
+	WaypointSymbol string          `json:"waypointSymbol"`
+	ShipSymbol     string          `json:"shipSymbol"`
+	TradeSymbol    TradeSymbol     `json:"tradeSymbol"`
+	Type           TransactionType `json:"type"`
+	Units          int64           `json:"units"`
+	PricePerUnit   int64           `json:"pricePerUnit"`
+	TotalPrice     int64           `json:"totalPrice"`
+	Timestamp      Time            `json:"timestamp"`
+
+	// shipyard transactions name the ship type and give a single price instead
+	ShipType ShipType `json:"shipType,omitempty"`
+	Price    int64    `json:"price,omitempty"`
 }
 
 type GetJumpGateResponseData struct {
@@ -244,17 +253,27 @@ type GetJumpGateResponse struct {
 	Connections []string `json:"connections"`
 }
 
-type TradeRoute struct {
-	BuyMarketplaceWaypointSymbol  string
-	SellMarketplaceWaypointSymbol string
-	BuyWaypoint                   Waypoint
-	BuyMarketTradeGood            TradeGood
-	SellWaypoint                  Waypoint
-	SellMarketTradeGood           TradeGood
-	TradeGoodSymbol               string
-	ProfitPerUnit                 int64
-	Distance                      float64
-	ProfitabilityRating           float64
+type PatchShipNavPayload struct {
+	FlightMode FlightMode `json:"flightMode"`
+}
+
+type PatchShipNavResponseData struct {
+	Data Nav `json:"data"`
+}
+
+type JumpShipPayload struct {
+	WaypointSymbol string `json:"waypointSymbol"`
+}
+
+type JumpShipResponseData struct {
+	Data JumpShipResponse `json:"data"`
+}
+
+type JumpShipResponse struct {
+	Nav         Nav         `json:"nav"`
+	Cooldown    Cooldown    `json:"cooldown"`
+	Transaction Transaction `json:"transaction"`
+	Agent       Agent       `json:"agent"`
 }
 
 type GetShipyardResponseData struct {
@@ -262,15 +281,15 @@ type GetShipyardResponseData struct {
 }
 
 type Shipyard struct {
-	Symbol           string        `json:"symbol"`
-	ShipTypes        []ShipType    `json:"shipTypes"`
-	Transactions     []Transaction `json:"transactions"`
-	Ships            []Ship        `json:"ships"`
-	ModificationsFee int64         `json:"modificationsFee"`
+	Symbol           string             `json:"symbol"`
+	ShipTypes        []ShipyardShipType `json:"shipTypes"`
+	Transactions     []Transaction      `json:"transactions"`
+	Ships            []Ship             `json:"ships"`
+	ModificationsFee int64              `json:"modificationsFee"`
 }
 
-type ShipType struct {
-	Type string `json:"type"`
+type ShipyardShipType struct {
+	Type ShipType `json:"type"`
 }
 
 type Requirements struct {
@@ -309,12 +328,28 @@ type Event struct {
 type RegisterAgentPayload struct {
 	Symbol  string `json:"symbol"`
 	Faction string `json:"faction"`
+	Email   string `json:"email,omitempty"`
 }
 
 type NavigateShipPayload struct {
 	WaypointSymbol string `json:"waypointSymbol"`
 }
 
+// ServerStatus is the document the API serves at its root. ResetDate is the day the universe was last
+// reset, agents and their tokens only last until the next one.
+type ServerStatus struct {
+	Status       string       `json:"status"`
+	Version      string       `json:"version"`
+	ResetDate    string       `json:"resetDate"`
+	Description  string       `json:"description"`
+	ServerResets ServerResets `json:"serverResets"`
+}
+
+type ServerResets struct {
+	Next      Time   `json:"next"`
+	Frequency string `json:"frequency"`
+}
+
 type RegisterAgentResponseData struct {
 	Data RegisterAgentResponse `json:"data"`
 }
@@ -343,21 +378,21 @@ type Contract struct {
 	Terms            Terms  `json:"terms"`
 	Accepted         bool   `json:"accepted"`
 	Fulfilled        bool   `json:"fulfilled"`
-	Expiration       string `json:"expiration"`
-	DeadlineToAccept string `json:"deadlineToAccept"`
+	Expiration       Time   `json:"expiration"`
+	DeadlineToAccept Time   `json:"deadlineToAccept"`
 }
 
 type Terms struct {
-	Deadline string    `json:"deadline"`
+	Deadline Time      `json:"deadline"`
 	Payment  Payment   `json:"payment"`
 	Deliver  []Deliver `json:"deliver"`
 }
 
 type Deliver struct {
-	TradeSymbol       string `json:"tradeSymbol"`
-	DestinationSymbol string `json:"destinationSymbol"`
-	UnitsRequired     int64  `json:"unitsRequired"`
-	UnitsFulfilled    int64  `json:"unitsFulfilled"`
+	TradeSymbol       TradeSymbol `json:"tradeSymbol"`
+	DestinationSymbol string      `json:"destinationSymbol"`
+	UnitsRequired     int64       `json:"unitsRequired"`
+	UnitsFulfilled    int64       `json:"unitsFulfilled"`
 }
 
 type Payment struct {
@@ -385,8 +420,8 @@ type DockShipResponse struct {
 }
 
 type PurchaseShipPayload struct {
-	ShipType       string `json:"shipType"`
-	WaypointSymbol string `json:"waypointSymbol"`
+	ShipType       ShipType `json:"shipType"`
+	WaypointSymbol string   `json:"waypointSymbol"`
 }
 
 type PurchaseShipResponseData struct {
@@ -400,15 +435,15 @@ type PurchaseShipResponse struct {
 }
 
 type InventoryItem struct {
-	Symbol      string `json:"symbol"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Units       int64  `json:"units"`
+	Symbol      TradeSymbol `json:"symbol"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Units       int64       `json:"units"`
 }
 
 type PurchaseCargoPayload struct {
-	Symbol string `json:"symbol"`
-	Units  int64  `json:"units"`
+	Symbol TradeSymbol `json:"symbol"`
+	Units  int64       `json:"units"`
 }
 
 type PurchaseCargoResponseData struct {
@@ -422,8 +457,8 @@ type PurchaseCargoResponse struct {
 }
 
 type SellCargoPayload struct {
-	Symbol string `json:"symbol"`
-	Units  int64  `json:"units"`
+	Symbol TradeSymbol `json:"symbol"`
+	Units  int64       `json:"units"`
 }
 
 type SellCargoResponseData struct {
@@ -454,3 +489,128 @@ type RefuelShipResponse struct {
 	Fuel        Fuel        `json:"fuel"`
 	Transaction Transaction `json:"transaction"`
 }
+
+type ListContractsResponseData struct {
+	Data []Contract `json:"data"`
+	Meta Meta       `json:"meta"`
+}
+
+type GetContractResponseData struct {
+	Data Contract `json:"data"`
+}
+
+type AcceptContractResponseData struct {
+	Data AcceptContractResponse `json:"data"`
+}
+
+type AcceptContractResponse struct {
+	Agent    Agent    `json:"agent"`
+	Contract Contract `json:"contract"`
+}
+
+type DeliverContractPayload struct {
+	ShipSymbol  string      `json:"shipSymbol"`
+	TradeSymbol TradeSymbol `json:"tradeSymbol"`
+	Units       int64       `json:"units"`
+}
+
+type DeliverContractResponseData struct {
+	Data DeliverContractResponse `json:"data"`
+}
+
+type DeliverContractResponse struct {
+	Contract Contract `json:"contract"`
+	Cargo    Cargo    `json:"cargo"`
+}
+
+type FulfillContractResponseData struct {
+	Data FulfillContractResponse `json:"data"`
+}
+
+type FulfillContractResponse struct {
+	Agent    Agent    `json:"agent"`
+	Contract Contract `json:"contract"`
+}
+
+type NegotiateContractResponseData struct {
+	Data NegotiateContractResponse `json:"data"`
+}
+
+type NegotiateContractResponse struct {
+	Contract Contract `json:"contract"`
+}
+
+type Survey struct {
+	Signature  string          `json:"signature"`
+	Symbol     string          `json:"symbol"`
+	Deposits   []SurveyDeposit `json:"deposits"`
+	Expiration Time            `json:"expiration"`
+	Size       string          `json:"size"`
+}
+
+type SurveyDeposit struct {
+	Symbol TradeSymbol `json:"symbol"`
+}
+
+type CreateSurveyResponseData struct {
+	Data CreateSurveyResponse `json:"data"`
+}
+
+type CreateSurveyResponse struct {
+	Cooldown Cooldown `json:"cooldown"`
+	Surveys  []Survey `json:"surveys"`
+}
+
+type ExtractResourcesResponseData struct {
+	Data ExtractResourcesResponse `json:"data"`
+}
+
+type ExtractResourcesResponse struct {
+	Cooldown   Cooldown   `json:"cooldown"`
+	Extraction Extraction `json:"extraction"`
+	Cargo      Cargo      `json:"cargo"`
+	Events     []Event    `json:"events"`
+}
+
+type Extraction struct {
+	ShipSymbol string `json:"shipSymbol"`
+	Yield      Yield  `json:"yield"`
+}
+
+type Yield struct {
+	Symbol TradeSymbol `json:"symbol"`
+	Units  int64       `json:"units"`
+}
+
+type RefinePayload struct {
+	Produce TradeSymbol `json:"produce"`
+}
+
+type RefineResponseData struct {
+	Data RefineResponse `json:"data"`
+}
+
+type RefineResponse struct {
+	Cargo    Cargo        `json:"cargo"`
+	Cooldown Cooldown     `json:"cooldown"`
+	Produced []RefineItem `json:"produced"`
+	Consumed []RefineItem `json:"consumed"`
+}
+
+type RefineItem struct {
+	TradeSymbol TradeSymbol `json:"tradeSymbol"`
+	Units       int64       `json:"units"`
+}
+
+type JettisonPayload struct {
+	Symbol TradeSymbol `json:"symbol"`
+	Units  int64       `json:"units"`
+}
+
+type JettisonResponseData struct {
+	Data JettisonResponse `json:"data"`
+}
+
+type JettisonResponse struct {
+	Cargo Cargo `json:"cargo"`
+}